@@ -0,0 +1,81 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Command telemetry summarizes the telemetry logs of the game.
+//
+// Usage:
+//
+//	go run ./cmd/telemetry [-edge distance] [log files...]
+//
+// Without log files, all of the logs in the user directory of the game are read.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/anilkonac/snake-ebiten/game/telemetry"
+)
+
+func main() {
+	edgeDistance := flag.Float64("edge", 80, "Distance in pixels from a world edge a death is counted near it")
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		dir, err := settings.Dir()
+		if err != nil {
+			exit(err)
+		}
+		if paths, err = filepath.Glob(filepath.Join(dir, telemetry.Dir, "*.jsonl")); err != nil {
+			exit(err)
+		}
+	}
+	if len(paths) == 0 {
+		exit(fmt.Errorf("no telemetry logs are found"))
+	}
+
+	var events []telemetry.Event
+	for _, path := range paths {
+		logEvents, err := readLog(path)
+		if err != nil {
+			exit(fmt.Errorf("%s: %w", path, err))
+		}
+		events = append(events, logEvents...)
+	}
+
+	fmt.Print(telemetry.Analyze(events, *edgeDistance))
+}
+
+func readLog(path string) ([]telemetry.Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return telemetry.ReadEvents(file)
+}
+
+func exit(err error) {
+	fmt.Fprintln(os.Stderr, "telemetry:", err)
+	os.Exit(1)
+}
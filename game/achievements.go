@@ -0,0 +1,192 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/anilkonac/snake-ebiten/game/telemetry"
+)
+
+// Achievement parameters
+const (
+	settingsAchievements = "achievements"
+	textAchievement      = "Achievement unlocked"
+	quickDeathTime       = 3.0 // seconds
+)
+
+const (
+	achievementFood = iota
+	achievementLength
+	achievementSurvive
+	achievementWraps
+	achievementWrapFood
+	achievementQuickDeath
+	achievementTotal
+)
+
+// achievement is unlocked when its progress reaches the goal.
+type achievement struct {
+	id   string // Key of its progress in the settings file
+	name string
+	desc string
+	goal int
+}
+
+var achievements = [achievementTotal]achievement{
+	achievementFood:       {"food50", "Glutton", "Eat 50 food in total", 50},
+	achievementLength:     {"length2000", "Long Story", "Reach a length of 2000 pixels", 2000},
+	achievementSurvive:    {"survive300", "Survivor", "Survive for 5 minutes in a game", 300},
+	achievementWraps:      {"wrap100", "Globetrotter", "Wrap around the screen 100 times", 100},
+	achievementWrapFood:   {"wrapFood", "Portal Snack", "Eat food right after wrapping around the screen", 1},
+	achievementQuickDeath: {"quickDeath", "Speedrun", "Die within 3 seconds", 1},
+}
+
+type achievementProgress struct {
+	Progress int  `json:"progress"`
+	Unlocked bool `json:"unlocked"`
+}
+
+// achievementTracker updates the progress of the achievements with the gameplay events.
+type achievementTracker struct {
+	progress  [achievementTotal]achievementProgress
+	simRate   int
+	wrapTick  int   // Tick of the last wrap in the game, negative if there is none
+	cheated   bool  // The game is changed by the console, its events are ignored
+	deathless bool  // The snake passes through itself in the game, so its time does not count for survival
+	unlocked  []int // Achievements unlocked since the last call of popUnlocked
+}
+
+var tracker achievementTracker
+
+func init() {
+	saved := make(map[string]achievementProgress)
+	if err := settings.Load(settingsAchievements, &saved); err != nil {
+		fmt.Println("Could not load the achievements:", err)
+	}
+	for iAchievement := range achievements {
+		tracker.progress[iAchievement] = saved[achievements[iAchievement].id]
+	}
+
+	telemetry.Subscribe(tracker.handle)
+}
+
+func (t *achievementTracker) handle(event telemetry.Event) {
+	numUnlocked := len(t.unlocked)
+
+	switch event.Kind {
+	case telemetry.KindGameStart:
+		t.simRate = event.SimRate
+		t.wrapTick = -1
+		t.cheated = false
+		mode := findMode(event.Mode)
+		t.deathless = (mode != nil) && mode.passThrough
+	case telemetry.KindCheat:
+		t.cheated = true
+	}
+	if !t.cheated {
+		t.progressWith(event)
+	}
+
+	// The counters change often, so they are saved only at the end of the game or with an unlock.
+	if (event.Kind == telemetry.KindGameOver) || (len(t.unlocked) > numUnlocked) {
+		t.save()
+	}
+}
+
+// progressWith updates the progress of the achievements with an event of a game that is not cheated.
+// Survival counts only in the modes the snake can die in.
+func (t *achievementTracker) progressWith(event telemetry.Event) {
+	switch event.Kind {
+	case telemetry.KindWrap:
+		t.wrapTick = event.Tick
+		t.add(achievementWraps, 1)
+	case telemetry.KindFoodEat:
+		t.add(achievementFood, 1)
+		if (t.wrapTick >= 0) && (t.seconds(event.Tick-t.wrapTick) <= wrapStyleWindow) {
+			t.add(achievementWrapFood, 1)
+		}
+	case telemetry.KindCollision:
+		if t.seconds(event.Tick) < quickDeathTime {
+			t.add(achievementQuickDeath, 1)
+		}
+	}
+	t.reach(achievementLength, int(event.Snake.Length))
+	if !t.deathless {
+		t.reach(achievementSurvive, int(t.seconds(event.Tick)))
+	}
+}
+
+func (t *achievementTracker) seconds(ticks int) float64 {
+	simRate := t.simRate
+	if simRate <= 0 {
+		simRate = param.SimRate
+	}
+	return float64(ticks) / float64(simRate)
+}
+
+// add increases the progress of the achievement.
+func (t *achievementTracker) add(index, amount int) {
+	t.reach(index, t.progress[index].Progress+amount)
+}
+
+// reach sets the progress of the achievement if the value is higher.
+func (t *achievementTracker) reach(index, value int) {
+	progress := &t.progress[index]
+	if progress.Unlocked || (value <= progress.Progress) {
+		return
+	}
+
+	progress.Progress = value
+	if progress.Progress >= achievements[index].goal {
+		progress.Progress = achievements[index].goal
+		progress.Unlocked = true
+		t.unlocked = append(t.unlocked, index)
+	}
+}
+
+// popUnlocked returns the achievements unlocked since its last call.
+func (t *achievementTracker) popUnlocked() []int {
+	unlocked := t.unlocked
+	t.unlocked = nil
+	return unlocked
+}
+
+func (t *achievementTracker) numUnlocked() int {
+	var numUnlocked int
+	for _, progress := range t.progress {
+		if progress.Unlocked {
+			numUnlocked++
+		}
+	}
+	return numUnlocked
+}
+
+func (t *achievementTracker) save() {
+	saved := make(map[string]achievementProgress, achievementTotal)
+	for iAchievement, progress := range t.progress {
+		saved[achievements[iAchievement].id] = progress
+	}
+	if err := settings.Save(settingsAchievements, saved); err != nil {
+		fmt.Println("Could not save the achievements:", err)
+	}
+}
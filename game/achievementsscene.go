@@ -0,0 +1,90 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Achievements scene layout parameters
+const (
+	textAchievementsTitle = "Achievements"
+	textAchievementsHelp  = "%s: Back"
+	achievementsRowHeight = 76
+	achievementsDescY     = 28 // Shift of the description below the name
+	achievementsBarX      = 680
+	achievementsBarWidth  = 200
+	achievementsBarHeight = 12
+)
+
+// achievementsScene is the gallery of the achievements with their progress.
+type achievementsScene struct{}
+
+func newAchievementsScene() *achievementsScene {
+	return &achievementsScene{}
+}
+
+func (a *achievementsScene) update() bool {
+	return input.IsActionJustPressed(input.ActionMenuBack)
+}
+
+func (a *achievementsScene) draw(screen *ebiten.Image) {
+	drawBackground(screen)
+
+	// Draw title with the number of unlocked achievements
+	title := fmt.Sprintf("%s %d/%d", textAchievementsTitle, tracker.numUnlocked(), achievementTotal)
+	boundTitle := text.BoundString(param.FontFaceScore, title)
+	text.Draw(screen, title, param.FontFaceScore,
+		(param.ScreenWidth-boundTitle.Size().X)/2-boundTitle.Min.X, controlsTitleShiftY-boundTitle.Min.Y, param.ColorScore)
+
+	for iAchievement := range achievements {
+		rowY := controlsTableShiftY + iAchievement*achievementsRowHeight
+		a.drawRow(screen, iAchievement, rowY)
+	}
+
+	text.Draw(screen, fmt.Sprintf(textAchievementsHelp, input.KeyLabel(input.ActionMenuBack)), fontFaceDebug, controlsLabelX, param.ScreenHeight-controlsHelpShiftY, param.ColorDebug)
+}
+
+// drawRow draws the name and description of the achievement with a bar of its progress.
+func (a *achievementsScene) drawRow(screen *ebiten.Image, iAchievement, rowY int) {
+	achievement := &achievements[iAchievement]
+	progress := tracker.progress[iAchievement]
+
+	clrName := param.ColorDebug
+	status := fmt.Sprintf("%d/%d", progress.Progress, achievement.goal)
+	if progress.Unlocked {
+		clrName = param.ColorScore
+		status = "Unlocked"
+	}
+	text.Draw(screen, achievement.name, fontFaceMenu, controlsLabelX, rowY, clrName)
+	text.Draw(screen, achievement.desc, fontFaceDebug, controlsLabelX, rowY+achievementsDescY, param.ColorDebug)
+
+	barY := float64(rowY - achievementsBarHeight)
+	fill := float64(progress.Progress) / float64(achievement.goal)
+	ebitenutil.DrawRect(screen, achievementsBarX, barY, achievementsBarWidth, achievementsBarHeight, param.ColorSnake1)
+	ebitenutil.DrawRect(screen, achievementsBarX, barY, achievementsBarWidth*fill, achievementsBarHeight, param.ColorSnake2)
+	text.Draw(screen, status, fontFaceDebug, achievementsBarX, rowY+achievementsDescY, param.ColorDebug)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/sound"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+// Adaptive music parameters
+const (
+	adaptiveFoodMax      = 40.0  // Food eaten for the full intensity from growth
+	adaptiveThemeCutoff  = 2500  // Cutoff of the theme stem at zero intensity [Hz]
+	adaptivePauseCutoff  = 400   // Cutoff of the whole mix while paused [Hz]
+	adaptiveOpenCutoff   = 20000 // [Hz]
+	adaptiveTensionDist  = param.SnakeWidth * 4
+	adaptiveThemeGainMin = 0.75
+	heartbeatBPM         = 72
+	heartbeatAmplitude   = 0.7
+)
+
+var (
+	musicAdaptive *sound.Music
+	adaptive      *sound.AdaptiveMusic
+	stemTheme     int
+	stemTension   int
+)
+
+// prepareAdaptiveMusic creates the adaptive music from the theme and a synthesized heartbeat tension stem.
+func prepareAdaptiveMusic(bytesMusic []byte) {
+	stream, err := vorbis.DecodeWithSampleRate(sound.SampleRate, bytes.NewReader(bytesMusic))
+	panicErr(err)
+
+	adaptive = sound.NewAdaptiveMusic()
+	stemTheme = adaptive.AddStem(stream, stream.Length(), adaptiveThemeGainMin)
+
+	heartbeat := heartbeatPCM()
+	stemTension = adaptive.AddStem(bytes.NewReader(heartbeat), int64(len(heartbeat)), 0)
+
+	musicAdaptive, err = sound.NewStreamMusic(adaptive, volumeMusic)
+	if err != nil {
+		fmt.Println("Could not create the adaptive music:", err)
+		musicAdaptive = nil
+	}
+}
+
+// heartbeatPCM synthesizes one "lub-dub" beat as 16 bit stereo PCM.
+func heartbeatPCM() []byte {
+	const beatSec = 60.0 / heartbeatBPM
+	numFrames := int(beatSec * sound.SampleRate)
+	pcm := make([]byte, numFrames*4)
+
+	thump := func(t, start, freq float64) float64 {
+		if t < start {
+			return 0
+		}
+		t -= start
+		return math.Sin(2*math.Pi*freq*t) * math.Exp(-t*25)
+	}
+
+	for iFrame := 0; iFrame < numFrames; iFrame++ {
+		t := float64(iFrame) / sound.SampleRate
+		sample := thump(t, 0, 55) + 0.7*thump(t, 0.28, 45)
+		value := uint16(int16(sample * heartbeatAmplitude * math.MaxInt16))
+		binary.LittleEndian.PutUint16(pcm[iFrame*4:], value)
+		binary.LittleEndian.PutUint16(pcm[iFrame*4+2:], value)
+	}
+	return pcm
+}
+
+// updateAdaptiveMusic blends the stems according to the game state.
+func updateAdaptiveMusic(g *gameScene) {
+	if !g.adaptiveMusic {
+		return
+	}
+
+	if g.paused {
+		adaptive.SetCutoff(adaptivePauseCutoff)
+		return
+	}
+	adaptive.SetCutoff(adaptiveOpenCutoff)
+
+	// Intensity rises as the snake grows and its speed moves from the initial speed toward the final speed.
+	growth := math.Min(float64(g.snake.FoodEaten)/adaptiveFoodMax, 1)
+	speedFinal := g.mode.speedFinal()
+	speed := math.Min(math.Abs(g.snake.Speed-param.SnakeSpeedInitial)/math.Abs(speedFinal-param.SnakeSpeedInitial), 1)
+	intensity := math.Min(growth+speed, 1)
+	adaptive.SetStemGain(stemTheme, adaptiveThemeGainMin+(1-adaptiveThemeGainMin)*intensity)
+	adaptive.SetStemCutoff(stemTheme, adaptiveThemeCutoff*math.Pow(adaptiveOpenCutoff/adaptiveThemeCutoff, intensity))
+
+	// Tension rises as the head gets close to its own body.
+	tension := 0.0
+	if !g.gameOver {
+		tension = 1 - math.Min(selfProximity(g.snake)/adaptiveTensionDist, 1)
+	}
+	adaptive.SetStemGain(stemTension, tension)
+}
+
+// selfProximity returns the distance between the head and the closest part of the body excluding the neck.
+func selfProximity(snake *s.Snake) float64 {
+	minDist := math.Inf(1)
+	if snake.UnitHead.Next == nil {
+		return minDist
+	}
+
+	head := snake.UnitHead.HeadCenter
+	for unit := snake.UnitHead.Next.Next; unit != nil; unit = unit.Next {
+		for iRect := uint8(0); iRect < unit.CompCollision.NumRects; iRect++ {
+			minDist = math.Min(minDist, distToRect(head, &unit.CompCollision.Rects[iRect]))
+		}
+	}
+	return math.Max(minDist-param.RadiusSnake, 0)
+}
+
+func distToRect(p c.Vec64, rect *c.RectF32) float64 {
+	pos := rect.Pos.To64()
+	size := rect.Size.To64()
+	dx := math.Max(math.Max(pos.X-p.X, 0), p.X-(pos.X+size.X))
+	dy := math.Max(math.Max(pos.Y-p.Y, 0), p.Y-(pos.Y+size.Y))
+	return math.Hypot(dx, dy)
+}
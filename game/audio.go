@@ -21,46 +21,36 @@ package game
 
 import (
 	"bytes"
+	"fmt"
 	"math/rand"
-	"time"
 
+	"github.com/anilkonac/snake-ebiten/game/sound"
 	res "github.com/anilkonac/snake-ebiten/resource"
-	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
 	"github.com/hajimehoshi/ebiten/v2/audio/wav"
 )
 
 const (
-	sampleRate    = 44100
-	volumeEating  = 0.45
-	volumeMusic   = 0.4
-	volumeHit     = 1.0
-	probEatingA   = 0.74
-	musicCheckSec = 2
-)
-
-type stateMusic uint8
-
-const (
-	musicOn stateMusic = iota
-	musicPaused
-	musicMuted
+	volumeEating    = 0.45
+	volumeMusic     = 0.4
+	volumeHit       = 1.0
+	probEatingA     = 0.74
+	numVoicesEating = 4
+	numVoicesHit    = 2
 )
 
 var (
-	audioContext  *audio.Context
-	playerHit     *audio.Player
-	playerMusic   *audio.Player
-	playerEatingA *audio.Player
-	playerEatingB *audio.Player
-	musicState    stateMusic
-	playSounds    = true
+	playlistTitle *sound.Playlist
+	playlistGame  *sound.Playlist
+	soundHit      *sound.Effect
+	soundEatingA  *sound.Effect
+	soundEatingB  *sound.Effect
 )
 
 func init() {
+	if err := sound.Init(); err != nil {
+		fmt.Println(err)
+	}
 	prepareAudio()
-	playerMusic.Play()
-	go repeatMusic()
 }
 
 func prepareAudio() {
@@ -77,67 +67,61 @@ func prepareAudio() {
 	bytesSoundHit, err := res.FS.ReadFile(res.PathSoundHit)
 	panicErr(err)
 
-	// Create players
-	audioContext = audio.NewContext(sampleRate)
-	playerEatingA = createPlayer(bytesSoundEating1, volumeEating)
-	playerEatingB = createPlayer(bytesSoundEating2, volumeEating)
-	playerHit = createPlayer(bytesSoundHit, volumeHit)
-
-	playerMusic = createMusicPlayer(bytesMusic)
-	playerMusic.SetVolume(volumeMusic)
-}
+	// Create sound effects
+	soundEatingA = createEffect(bytesSoundEating1, volumeEating, numVoicesEating)
+	soundEatingB = createEffect(bytesSoundEating2, volumeEating, numVoicesEating)
+	soundHit = createEffect(bytesSoundHit, volumeHit, numVoicesHit)
 
-func createPlayer(src []byte, volume float64) *audio.Player {
-	stream, err := wav.DecodeWithSampleRate(sampleRate, bytes.NewReader(src))
-	panicErr(err)
+	// Create playlists. The title scene plays the theme only, the game scene plays the user's music as well.
+	trackTheme := sound.NewTrack(res.PathMusic, bytesMusic, volumeMusic)
+	playlistTitle = sound.NewPlaylist(trackTheme)
+	playlistGame = sound.NewPlaylist(trackTheme)
 
-	player, err := audioContext.NewPlayer(stream)
-	panicErr(err)
+	userTracks, err := sound.UserTracks(volumeMusic)
+	if err != nil {
+		fmt.Println("Could not read the user music folder:", err)
+	}
+	playlistGame.Add(userTracks...)
 
-	player.SetVolume(volume)
-	return player
+	if sound.Enabled() {
+		prepareAdaptiveMusic(bytesMusic)
+	}
 }
 
-func createMusicPlayer(src []byte) *audio.Player {
-	stream, err := vorbis.DecodeWithSampleRate(sampleRate, bytes.NewReader(src))
+// createEffect decodes a wav file into a sound effect. A silent effect is returned if the audio is disabled.
+func createEffect(src []byte, volume float64, numVoices int) *sound.Effect {
+	if !sound.Enabled() {
+		effect, _ := sound.NewEffect(nil, volume, numVoices)
+		return effect
+	}
+
+	stream, err := wav.DecodeWithSampleRate(sound.SampleRate, bytes.NewReader(src))
 	panicErr(err)
 
-	player, err := audioContext.NewPlayer(stream)
+	effect, err := sound.NewEffect(stream, volume, numVoices)
 	panicErr(err)
 
-	return player
+	return effect
 }
 
-func playSoundEating() {
-	if !playSounds {
+// playSoundEating plays the eating sound. The pitch of the synthesized sound rises with the given step.
+func playSoundEating(step int) {
+	if sound.SynthSFX() {
+		playSynthEating(step)
 		return
 	}
 
-	var player *audio.Player
 	if rand.Float32() < probEatingA {
-		player = playerEatingA
+		soundEatingA.Play()
 	} else {
-		player = playerEatingB
+		soundEatingB.Play()
 	}
-	player.Rewind()
-	player.Play()
 }
 
 func playSoundHit() {
-	if !playSounds {
+	if sound.SynthSFX() {
+		playSynthHit()
 		return
 	}
-	playerHit.Rewind()
-	playerHit.Play()
-}
-
-// Goroutine
-func repeatMusic() {
-	ticker := time.NewTicker(time.Second * musicCheckSec)
-	for range ticker.C {
-		if (musicState == musicOn) && !playerMusic.IsPlaying() {
-			playerMusic.Rewind()
-			playerMusic.Play()
-		}
-	}
+	soundHit.Play()
 }
@@ -0,0 +1,53 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/shader"
+	"github.com/anilkonac/snake-ebiten/game/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	shaderBackground   = shader.New(shader.PathBackground)
+	backgroundDrawOpts = ebiten.DrawRectShaderOptions{
+		Uniforms: map[string]interface{}{},
+	}
+)
+
+// drawBackground fills the screen with the background of the current theme.
+func drawBackground(screen *ebiten.Image) {
+	if theme.Current().Background != theme.BackgroundGradient {
+		screen.Fill(param.ColorBackground)
+		return
+	}
+
+	clr := param.ColorBackground
+	backgroundDrawOpts.Uniforms["Color"] = []float32{float32(clr.R) / 255, float32(clr.G) / 255, float32(clr.B) / 255, 1}
+	backgroundDrawOpts.Uniforms["ScreenSize"] = []float32{float32(param.ScreenWidth), float32(param.ScreenHeight)}
+	screen.DrawRectShader(param.ScreenWidth, param.ScreenHeight, shaderBackground, &backgroundDrawOpts)
+}
+
+// selectTheme switches to the theme with the given offset in the theme list and reloads the theme fonts.
+func selectTheme(offset int) {
+	th := theme.Select(offset)
+	loadFonts(th.Fonts)
+}
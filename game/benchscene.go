@@ -0,0 +1,254 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/object"
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/skin"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Benchmark parameters
+const (
+	benchSnakesInitial = 300
+	benchSnakesStep    = 100
+	benchSnakesMax     = 2000
+	benchWarmUpTime    = 1.0 // seconds, not included in the averages after the snake count changes
+	textBenchHelp      = "Up/Down: Snakes   Esc: Back"
+	benchPanelWidth    = 360
+	benchPanelHeight   = 230
+	benchPanelPadding  = 16
+	benchLineHeight    = 26
+)
+
+// benchScene runs hundreds of bot snakes with the frame rate limit removed and reports the frame rates.
+type benchScene struct {
+	snakes    []*s.Snake
+	turnTimes []float32 // Seconds until the next turn of each snake
+	time      float32   // Seconds since the snake count changed
+	samples   int
+	sumTPS    float64
+	sumFPS    float64
+	minFPS    float64
+
+	// Collision checks of the heads against the units of the other snakes
+	broadphase *object.Broadphase
+	candidates []object.Collidable
+	timeBrute  time.Duration // Total time of the brute force checks since the snake count changed
+	timeBroad  time.Duration // Total time of the broadphase checks since the snake count changed
+	numChecks  int
+	hitsBrute  int // Heads touching another snake in the last tick
+	hitsBroad  int
+}
+
+func newBenchScene() *benchScene {
+	param.TeleportEnabled = true
+	s.MouthEnabled = false
+	ebiten.SetFPSMode(ebiten.FPSModeVsyncOffMaximum)
+	c.Cam.Reset(c.Vec64{X: float64(param.WorldWidth) / 2.0, Y: float64(param.WorldHeight) / 2.0})
+
+	scene := &benchScene{broadphase: object.NewBroadphase()}
+	scene.resize(benchSnakesInitial)
+	return scene
+}
+
+// resize adds or removes snakes to reach the count and restarts the measurement.
+func (b *benchScene) resize(count int) {
+	for len(b.snakes) < count {
+		length := dumbSnakeLengthMin + rand.Intn(dumbSnakeLengthDiff)
+		speed := dumbSnakeSpeedMin + rand.Float64()*dumbSnakeSpeedDiff
+		snake := s.NewSnakeRandDirLoc(uint16(length), speed, snakeColors[rand.Intn(len(snakeColors))])
+		snake.Skin = skin.Random()
+		b.snakes = append(b.snakes, snake)
+		b.turnTimes = append(b.turnTimes, rand.Float32()*turnTimeMaxSec)
+	}
+	b.snakes = b.snakes[:count]
+	b.turnTimes = b.turnTimes[:count]
+
+	b.time = 0
+	b.samples = 0
+	b.sumTPS = 0
+	b.sumFPS = 0
+	b.minFPS = 0
+	b.timeBrute = 0
+	b.timeBroad = 0
+	b.numChecks = 0
+}
+
+func (b *benchScene) update() bool {
+	switch {
+	case input.IsKeyJustPressed(ebiten.KeyUp) && len(b.snakes) < benchSnakesMax:
+		b.resize(len(b.snakes) + benchSnakesStep)
+	case input.IsKeyJustPressed(ebiten.KeyDown) && len(b.snakes) > benchSnakesStep:
+		b.resize(len(b.snakes) - benchSnakesStep)
+	case input.IsKeyJustPressed(ebiten.KeyEscape):
+		b.report()
+		ebiten.SetFPSMode(ebiten.FPSModeVsyncOn)
+		return true
+	}
+
+	for iSnake, snake := range b.snakes {
+		b.turnTimes[iSnake] -= float32(param.DeltaTime)
+		if b.turnTimes[iSnake] <= 0 {
+			b.turnTimes[iSnake] = turnTimeMinSec + rand.Float32()*turnTimeDiff
+			dirCurrent := snake.LastDirection()
+			dirNew := s.DirectionUp
+			if dirCurrent.IsVertical() {
+				dirNew = s.DirectionLeft
+			}
+			if rand.Intn(2) == 0 {
+				dirNew = dirNew.Opposite()
+			}
+			snake.TurnTo(s.NewTurn(dirCurrent, dirNew), false)
+		}
+		snake.Update(param.MouthAnimStartDistance)
+	}
+
+	b.checkCollisions()
+	b.measure()
+	return false
+}
+
+// checkCollisions finds the heads touching the other snakes with both the brute force and the broadphase
+// paths, and measures their times. The collisions are only counted, the snakes keep moving. A head touching
+// a joint touches the capsules of both units, so the heads are counted instead of the units.
+func (b *benchScene) checkCollisions() {
+	timeStart := time.Now()
+	b.hitsBrute = 0
+	for _, snake := range b.snakes {
+		hit := false
+		for _, other := range b.snakes {
+			if other == snake {
+				continue
+			}
+			for unit := other.UnitHead; (unit != nil) && !hit; unit = unit.Next {
+				_, hit = snake.HeadCircle(param.RadiusSnake).OverlapsCapsule(unit.Capsule())
+			}
+		}
+		if hit {
+			b.hitsBrute++
+		}
+	}
+	timeBrute := time.Since(timeStart)
+
+	timeStart = time.Now()
+	b.hitsBroad = 0
+	b.broadphase.Clear()
+	for _, snake := range b.snakes {
+		for unit := snake.UnitHead; unit != nil; unit = unit.Next {
+			b.broadphase.Insert(unit)
+		}
+	}
+	for _, snake := range b.snakes {
+		b.candidates = b.broadphase.Candidates(snake.UnitHead, b.candidates[:0])
+		for _, candidate := range b.candidates {
+			unit := candidate.(*s.Unit)
+			if snake.Owns(unit) {
+				continue
+			}
+			if _, overlaps := snake.HeadCircle(param.RadiusSnake).OverlapsCapsule(unit.Capsule()); overlaps {
+				b.hitsBroad++
+				break
+			}
+		}
+	}
+	timeBroad := time.Since(timeStart)
+
+	if b.time >= benchWarmUpTime {
+		b.timeBrute += timeBrute
+		b.timeBroad += timeBroad
+		b.numChecks++
+	}
+}
+
+// checkTimes returns the average times of the collision checks per tick in microseconds.
+func (b *benchScene) checkTimes() (brute, broad float64) {
+	if b.numChecks == 0 {
+		return 0, 0
+	}
+	return float64(b.timeBrute.Microseconds()) / float64(b.numChecks), float64(b.timeBroad.Microseconds()) / float64(b.numChecks)
+}
+
+// measure samples the frame rates once a second after the warm-up.
+func (b *benchScene) measure() {
+	timePrev := b.time
+	b.time += float32(param.DeltaTime)
+	if (b.time < benchWarmUpTime) || (int(b.time) == int(timePrev)) {
+		return
+	}
+
+	fps := ebiten.ActualFPS()
+	b.samples++
+	b.sumTPS += clock.actualTPS()
+	b.sumFPS += fps
+	if (b.samples == 1) || (fps < b.minFPS) {
+		b.minFPS = fps
+	}
+}
+
+func (b *benchScene) averages() (tps, fps float64) {
+	if b.samples == 0 {
+		return 0, 0
+	}
+	return b.sumTPS / float64(b.samples), b.sumFPS / float64(b.samples)
+}
+
+// report prints the result of the benchmark, so it can be compared between runs.
+func (b *benchScene) report() {
+	avgTPS, avgFPS := b.averages()
+	timeBrute, timeBroad := b.checkTimes()
+	fmt.Printf("Benchmark: %d snakes, %d samples, avg TPS: %.1f, avg FPS: %.1f, min FPS: %.1f, "+
+		"collision checks: brute force %.0f us, broadphase %.0f us\n",
+		len(b.snakes), b.samples, avgTPS, avgFPS, b.minFPS, timeBrute, timeBroad)
+}
+
+func (b *benchScene) draw(screen *ebiten.Image) {
+	drawBackground(screen)
+	for _, snake := range b.snakes {
+		snake.Draw(screen)
+	}
+
+	// Draw the results panel
+	ebitenutil.DrawRect(screen, benchPanelPadding, benchPanelPadding, benchPanelWidth, benchPanelHeight, fadeColor(param.ColorBackground, 200))
+	avgTPS, avgFPS := b.averages()
+	timeBrute, timeBroad := b.checkTimes()
+	lines := [...]string{
+		fmt.Sprintf("Snakes: %d", len(b.snakes)),
+		fmt.Sprintf("TPS: %.1f   FPS: %.1f", clock.actualTPS(), ebiten.ActualFPS()),
+		fmt.Sprintf("Avg TPS: %.1f   Avg FPS: %.1f", avgTPS, avgFPS),
+		fmt.Sprintf("Min FPS: %.1f", b.minFPS),
+		fmt.Sprintf("Brute force: %.0f us  %d hits", timeBrute, b.hitsBrute),
+		fmt.Sprintf("Broadphase:  %.0f us  %d hits", timeBroad, b.hitsBroad),
+		textBenchHelp,
+	}
+	for iLine, line := range lines {
+		text.Draw(screen, line, fontFaceDebug, 2*benchPanelPadding, 2*benchPanelPadding+(iLine+1)*benchLineHeight-benchLineHeight/4, param.ColorDebug)
+	}
+}
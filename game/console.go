@@ -0,0 +1,480 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"math/rand"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/object"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/anilkonac/snake-ebiten/game/telemetry"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Console parameters
+const (
+	consoleKey            = ebiten.KeyGraveAccent
+	consolePrompt         = "> "
+	consoleLinesMax       = 200
+	consoleLineSpace      = 22
+	consolePaddingX       = 10
+	consolePaddingY       = 8
+	consoleGrowMax        = 1000
+	consoleRepeatDelay    = 400 * time.Millisecond // Time a key is held before it repeats
+	consoleRepeatInterval = 50 * time.Millisecond  // Time between the repeats
+	screenshotsDir        = "screenshots"
+)
+
+var colorConsoleBackground = color.RGBA{0, 0, 0, 200}
+
+var errConsoleArgs = errors.New("wrong number of arguments")
+
+// consoleCommand is run with the arguments typed after its name. The returned text is printed to the console.
+type consoleCommand struct {
+	usage string
+	help  string
+	run   func(g *gameScene, args []string) (string, error)
+}
+
+var consoleCommands map[string]consoleCommand
+
+func init() {
+	// Commands are set in init, since help refers to the command map itself.
+	consoleCommands = map[string]consoleCommand{
+		"help":       {"help", "List the commands", runHelp},
+		"grow":       {"grow [N]", "Grow the snake N times as if it has eaten", runGrow},
+		"speed":      {"speed [X]", "Print or set the speed of the snake in pixels per second", runSpeed},
+		"spawnfood":  {"spawnfood x y", "Put the food at the position", runSpawnFood},
+		"teleport":   {"teleport x y", "Move the whole snake so that its head is at the position", runTeleport},
+		"godmode":    {"godmode", "Toggle the collisions of the snake with itself", runGodMode},
+		"seed":       {"seed [N]", "Seed the random numbers with N or with the time, and print the seed", runSeed},
+		"setparam":   {"setparam [name value]", "List the parameters or set one of them", runSetParam},
+		"dumpunits":  {"dumpunits", "Print the units of the snake from the head to the tail", runDumpUnits},
+		"screenshot": {"screenshot", "Save the game screen without the console", runScreenshot},
+	}
+}
+
+// consoleParams are the variables that can be changed with setparam.
+var consoleParams = map[string]interface{}{
+	"timeScale":     &param.TimeScale,
+	"teleport":      &param.TeleportEnabled,
+	"printFPS":      &param.PrintFPS,
+	"debugUnits":    &param.DebugUnits,
+	"debugTurns":    &param.DebugTurns,
+	"maxQueueDepth": &turnPolicy.MaxQueueDepth,
+	"staleAfterMs":  &turnPolicy.StaleAfterMs,
+	"preTurnWindow": &turnPolicy.PreTurnWindow,
+	"uTurnMacro":    &turnPolicy.UTurnMacro,
+	"bufferInputs":  &turnPolicy.BufferInputs,
+}
+
+// console is a drop-down overlay of the game scene that runs commands to inspect and change the game.
+type console struct {
+	open                bool
+	input               []rune
+	chars               []rune   // Buffer of the typed characters in a tick
+	lines               []string // Output, the last line is the newest
+	history             []string // Entered commands
+	iHistory            int
+	screenshotRequested bool
+}
+
+// update handles the console keys and returns true if the console is open, so the game should wait.
+func (con *console) update(g *gameScene) bool {
+	if input.IsKeyJustPressed(consoleKey) {
+		con.open = !con.open
+		return true
+	}
+	if !con.open {
+		return false
+	}
+
+	con.chars = input.AppendInputChars(con.chars[:0])
+	for _, char := range con.chars {
+		if (char != '`') && (char != '~') {
+			con.input = append(con.input, char)
+		}
+	}
+
+	switch {
+	case input.IsKeyJustPressed(ebiten.KeyEnter):
+		con.execute(g)
+	case input.IsKeyRepeated(ebiten.KeyBackspace, consoleRepeatDelay, consoleRepeatInterval) && (len(con.input) > 0):
+		con.input = con.input[:len(con.input)-1]
+	case input.IsKeyJustPressed(ebiten.KeyUp) && (con.iHistory > 0):
+		con.iHistory--
+		con.input = []rune(con.history[con.iHistory])
+	case input.IsKeyJustPressed(ebiten.KeyDown) && (con.iHistory < len(con.history)):
+		con.iHistory++
+		con.input = con.input[:0]
+		if con.iHistory < len(con.history) {
+			con.input = []rune(con.history[con.iHistory])
+		}
+	case input.IsKeyJustPressed(ebiten.KeyEscape):
+		con.open = false
+	}
+
+	return true
+}
+
+func (con *console) execute(g *gameScene) {
+	line := strings.TrimSpace(string(con.input))
+	con.input = con.input[:0]
+	if line == "" {
+		return
+	}
+	con.history = append(con.history, line)
+	con.iHistory = len(con.history)
+	con.print(consolePrompt + line)
+
+	fields := strings.Fields(line)
+	command, ok := consoleCommands[strings.ToLower(fields[0])]
+	if !ok {
+		con.print("Unknown command " + fields[0] + ", type help for the list")
+		return
+	}
+
+	output, err := command.run(g, fields[1:])
+	if err != nil {
+		con.print("Error: " + err.Error())
+		con.print("Usage: " + command.usage)
+		return
+	}
+	if output != "" {
+		con.print(output)
+	}
+}
+
+// print adds the text to the output. Old lines are dropped when there are too many.
+func (con *console) print(msg string) {
+	con.lines = append(con.lines, strings.Split(msg, "\n")...)
+	if len(con.lines) > consoleLinesMax {
+		con.lines = con.lines[len(con.lines)-consoleLinesMax:]
+	}
+}
+
+// draw takes the requested screenshot of the game and draws the console on the top half of the screen.
+func (con *console) draw(screen *ebiten.Image) {
+	if con.screenshotRequested {
+		con.screenshotRequested = false
+		if location, err := saveScreenshot(screen); err != nil {
+			con.print("Could not save the screenshot: " + err.Error())
+		} else {
+			con.print("Screenshot saved to " + location)
+		}
+	}
+
+	if !con.open {
+		return
+	}
+
+	height := param.ScreenHeight / 2
+	ebitenutil.DrawRect(screen, 0, 0, float64(param.ScreenWidth), float64(height), colorConsoleBackground)
+
+	// Draw the input line at the bottom and the output upwards from it
+	lineY := height - consolePaddingY
+	text.Draw(screen, consolePrompt+string(con.input)+"_", fontFaceDebug, consolePaddingX, lineY, param.ColorScore)
+	for iLine := len(con.lines) - 1; iLine >= 0; iLine-- {
+		lineY -= consoleLineSpace
+		if lineY < consoleLineSpace {
+			break
+		}
+		text.Draw(screen, con.lines[iLine], fontFaceDebug, consolePaddingX, lineY, param.ColorDebug)
+	}
+}
+
+// saveScreenshot writes the image as a PNG file next to the settings file and returns its location.
+func saveScreenshot(img *ebiten.Image) (string, error) {
+	rgba := image.NewRGBA(img.Bounds())
+	img.ReadPixels(rgba.Pix)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rgba); err != nil {
+		return "", err
+	}
+	return settings.WriteUserFile(path.Join(screenshotsDir, time.Now().Format("2006-01-02_15-04-05")+".png"), buf.Bytes())
+}
+
+func runHelp(*gameScene, []string) (string, error) {
+	names := make([]string, 0, len(consoleCommands))
+	for name := range consoleCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for iName, name := range names {
+		command := consoleCommands[name]
+		lines[iName] = fmt.Sprintf("%-24s %s", command.usage, command.help)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func runGrow(g *gameScene, args []string) (string, error) {
+	times := 1
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		if times, err = strconv.Atoi(args[0]); err != nil {
+			return "", err
+		}
+		if (times < 1) || (times > consoleGrowMax) {
+			return "", fmt.Errorf("N must be between 1 and %d", consoleGrowMax)
+		}
+	default:
+		return "", errConsoleArgs
+	}
+
+	for iTime := 0; iTime < times; iTime++ {
+		g.snake.Grow()
+	}
+	g.cheat("grow")
+	return fmt.Sprintf("Grown %d times, food eaten: %d", times, g.snake.FoodEaten), nil
+}
+
+func runSpeed(g *gameScene, args []string) (string, error) {
+	switch len(args) {
+	case 0:
+	case 1:
+		speed, err := strconv.ParseFloat(args[0], 64)
+		if err != nil {
+			return "", err
+		}
+		if speed <= 0 {
+			return "", errors.New("speed must be positive")
+		}
+		g.snake.Speed = speed
+		g.cheat("speed")
+	default:
+		return "", errConsoleArgs
+	}
+	return fmt.Sprintf("Speed: %.1f", g.snake.Speed), nil
+}
+
+func runSpawnFood(g *gameScene, args []string) (string, error) {
+	pos, err := parseWorldPos(args)
+	if err != nil {
+		return "", err
+	}
+	g.food = object.NewFood(pos.To32())
+	g.cheat("spawnfood")
+	return fmt.Sprintf("Food spawned at (%.0f, %.0f), it moves if it is on the snake", pos.X, pos.Y), nil
+}
+
+func runTeleport(g *gameScene, args []string) (string, error) {
+	pos, err := parseWorldPos(args)
+	if err != nil {
+		return "", err
+	}
+	g.snake.Teleport(pos)
+	c.Cam.Reset(pos)
+	g.registerCollidables()
+	g.cheat("teleport")
+	return fmt.Sprintf("Teleported to (%.0f, %.0f)", pos.X, pos.Y), nil
+}
+
+// parseWorldPos parses the x and y arguments of a position in the world.
+func parseWorldPos(args []string) (c.Vec64, error) {
+	if len(args) != 2 {
+		return c.Vec64{}, errConsoleArgs
+	}
+
+	x, err := strconv.ParseFloat(args[0], 64)
+	if err != nil {
+		return c.Vec64{}, err
+	}
+	y, err := strconv.ParseFloat(args[1], 64)
+	if err != nil {
+		return c.Vec64{}, err
+	}
+	if (x < 0) || (x >= float64(param.WorldWidth)) || (y < 0) || (y >= float64(param.WorldHeight)) {
+		return c.Vec64{}, fmt.Errorf("position must be inside of the world (%dx%d)", param.WorldWidth, param.WorldHeight)
+	}
+	return c.Vec64{X: x, Y: y}, nil
+}
+
+// runGodMode removes the obstacles of the snake, so its movement is not swept and it cannot hit itself.
+func runGodMode(g *gameScene, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errConsoleArgs
+	}
+
+	g.godMode = !g.godMode
+	g.setObstacles()
+	g.cheat("godmode")
+	if g.godMode {
+		return "God mode on", nil
+	}
+	return "God mode off", nil
+}
+
+// cheat marks the game as changed by the console command, so its score and achievements are not recorded.
+func (g *gameScene) cheat(command string) {
+	g.cheated = true
+	g.publishEvent(telemetry.Event{Kind: telemetry.KindCheat, Command: command})
+}
+
+func runSeed(_ *gameScene, args []string) (string, error) {
+	seed := time.Now().UnixNano()
+	switch len(args) {
+	case 0:
+	case 1:
+		var err error
+		if seed, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			return "", err
+		}
+	default:
+		return "", errConsoleArgs
+	}
+
+	rand.Seed(seed)
+	return fmt.Sprintf("Seed: %d", seed), nil
+}
+
+func runSetParam(g *gameScene, args []string) (string, error) {
+	switch len(args) {
+	case 0:
+		names := make([]string, 0, len(consoleParams))
+		for name := range consoleParams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		lines := make([]string, len(names))
+		for iName, name := range names {
+			lines[iName] = fmt.Sprintf("%s = %v", name, paramValue(consoleParams[name]))
+		}
+		return strings.Join(lines, "\n"), nil
+	case 2:
+	default:
+		return "", errConsoleArgs
+	}
+
+	name, value := args[0], args[1]
+	variable, ok := consoleParams[name]
+	if !ok {
+		return "", errors.New("unknown parameter " + name)
+	}
+
+	var err error
+	switch variable := variable.(type) {
+	case *float64:
+		var number float64
+		if number, err = parseParamFloat(name, value); err == nil {
+			*variable = number
+		}
+	case *int:
+		*variable, err = strconv.Atoi(value)
+	case *bool:
+		*variable, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return "", err
+	}
+	g.cheat("setparam")
+
+	// The turn policy is kept in the settings file when the user changes it.
+	switch variable {
+	case &turnPolicy.MaxQueueDepth, &turnPolicy.StaleAfterMs, &turnPolicy.PreTurnWindow, &turnPolicy.UTurnMacro,
+		&turnPolicy.BufferInputs:
+		if err := settings.Save(settingsTurnPolicy, &turnPolicy); err != nil {
+			fmt.Println("Could not save the turn policy:", err)
+		}
+	}
+	return fmt.Sprintf("%s = %v", name, paramValue(variable)), nil
+}
+
+// parseParamFloat parses the value of a float parameter. Values that are not finite are rejected and the time
+// scale is clamped to (0, timeScaleMax].
+func parseParamFloat(name, value string) (float64, error) {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, errors.New(name + " must be a finite number")
+	}
+	if name == "timeScale" {
+		if number <= 0 {
+			return 0, errors.New("timeScale must be positive")
+		}
+		number = math.Min(number, timeScaleMax)
+	}
+	return number, nil
+}
+
+// paramValue dereferences the pointer of a console parameter.
+func paramValue(variable interface{}) interface{} {
+	switch variable := variable.(type) {
+	case *float64:
+		return *variable
+	case *int:
+		return *variable
+	case *bool:
+		return *variable
+	}
+	return nil
+}
+
+// runDumpUnits prints the units to the console and to the standard output, so they can be copied.
+func runDumpUnits(g *gameScene, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errConsoleArgs
+	}
+
+	lines := []string{fmt.Sprintf("Length: %.2f  Speed: %.1f  Food eaten: %d", g.snake.Length(), g.snake.Speed, g.snake.FoodEaten)}
+	iUnit := 0
+	for unit := g.snake.UnitHead; unit != nil; unit = unit.Next {
+		lines = append(lines, fmt.Sprintf("%d: %-5s head (%.2f, %.2f) length %.2f",
+			iUnit, unit.Direction, unit.HeadCenter.X, unit.HeadCenter.Y, unit.Length()))
+		iUnit++
+	}
+
+	dump := strings.Join(lines, "\n")
+	fmt.Println(dump)
+	return dump, nil
+}
+
+// runScreenshot requests a screenshot from the next frame. It is taken before the console is drawn.
+func runScreenshot(g *gameScene, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", errConsoleArgs
+	}
+	g.console.screenshotRequested = true
+	return "", nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Controls scene layout parameters
+const (
+	textControlsTitle     = "Controls"
+	textControlsHelp      = "Arrows: Select   Enter: Rebind   Backspace: Clear   R: Reset   Esc: Save & Back"
+	textControlsWaiting   = "Press..."
+	textControlsConflict  = "Conflicting bindings are marked in red"
+	controlsTitleShiftY   = 30
+	controlsTableShiftY   = 120
+	controlsRowHeight     = 40
+	controlsLabelX        = 60
+	controlsColumnX       = 360
+	controlsColumnWidth   = 190
+	controlsCellPaddingX  = 10
+	controlsNumColumns    = input.NumKeySlots + 1 // Key slots + gamepad button
+	controlsColumnGamepad = input.NumKeySlots
+	controlsHelpShiftY    = 12
+)
+
+var controlsColumnTitles = [controlsNumColumns]string{"Key 1", "Key 2", "Gamepad"}
+
+type controlsScene struct {
+	selectedAction input.Action
+	selectedColumn int
+	waiting        bool // Waiting for a key or button press to bind
+	finished       bool
+	conflicts      [input.ActionTotal]bool
+	scroll         input.Action // First visible action
+}
+
+func newControlsScene() *controlsScene {
+	return &controlsScene{
+		conflicts: input.Conflicts(),
+	}
+}
+
+func (c *controlsScene) update() bool {
+	if c.waiting {
+		c.handleRebind()
+		return false
+	}
+
+	switch {
+	case input.IsKeyJustPressed(ebiten.KeyUp) && c.selectedAction > 0:
+		c.selectedAction--
+	case input.IsKeyJustPressed(ebiten.KeyDown) && c.selectedAction < input.ActionTotal-1:
+		c.selectedAction++
+	case input.IsKeyJustPressed(ebiten.KeyLeft) && c.selectedColumn > 0:
+		c.selectedColumn--
+	case input.IsKeyJustPressed(ebiten.KeyRight) && c.selectedColumn < controlsNumColumns-1:
+		c.selectedColumn++
+	case input.IsKeyJustPressed(ebiten.KeyEnter):
+		c.waiting = true
+	case input.IsKeyJustPressed(ebiten.KeyBackspace) || input.IsKeyJustPressed(ebiten.KeyDelete):
+		if c.selectedColumn == controlsColumnGamepad {
+			input.UnbindButtons(c.selectedAction)
+		} else {
+			input.UnbindKey(c.selectedAction, c.selectedColumn)
+		}
+		c.conflicts = input.Conflicts()
+	case input.IsKeyJustPressed(ebiten.KeyR):
+		input.ResetBindings()
+		c.conflicts = input.Conflicts()
+	case input.IsKeyJustPressed(ebiten.KeyEscape):
+		if err := input.SaveBindings(); err != nil {
+			fmt.Println("Could not save the key bindings:", err)
+		}
+		return true
+	}
+
+	return false
+}
+
+func (c *controlsScene) handleRebind() {
+	if input.IsKeyJustPressed(ebiten.KeyEscape) {
+		c.waiting = false
+		return
+	}
+
+	if c.selectedColumn == controlsColumnGamepad {
+		if button, ok := input.JustPressedButton(); ok {
+			input.BindButton(c.selectedAction, button)
+			c.waiting = false
+		}
+	} else if key, ok := input.JustPressedKey(); ok {
+		input.BindKey(c.selectedAction, c.selectedColumn, key)
+		c.waiting = false
+	}
+
+	c.conflicts = input.Conflicts()
+}
+
+func (c *controlsScene) draw(screen *ebiten.Image) {
+	drawBackground(screen)
+
+	// Draw title
+	boundTitle := text.BoundString(param.FontFaceScore, textControlsTitle)
+	text.Draw(screen, textControlsTitle, param.FontFaceScore,
+		(param.ScreenWidth-boundTitle.Size().X)/2-boundTitle.Min.X, controlsTitleShiftY-boundTitle.Min.Y, param.ColorScore)
+
+	// Draw column titles
+	rowY := controlsTableShiftY - controlsRowHeight
+	for column, title := range controlsColumnTitles {
+		text.Draw(screen, title, fontFaceMenu, controlsColumnX+column*controlsColumnWidth+controlsCellPaddingX, rowY, param.ColorSnake2)
+	}
+
+	// Scroll the actions so that the selected one is visible, the last rows are left for the texts
+	numVisible := input.Action((param.ScreenHeight - controlsTableShiftY - 2*controlsRowHeight) / controlsRowHeight)
+	if c.selectedAction < c.scroll {
+		c.scroll = c.selectedAction
+	} else if c.selectedAction >= c.scroll+numVisible {
+		c.scroll = c.selectedAction - numVisible + 1
+	}
+
+	// Draw a row for each visible action
+	for action := c.scroll; (action < input.ActionTotal) && (action < c.scroll+numVisible); action++ {
+		rowY = controlsTableShiftY + int(action-c.scroll)*controlsRowHeight
+		c.drawRow(screen, action, rowY)
+	}
+
+	// Draw conflict warning and help text
+	bottomY := param.ScreenHeight - controlsHelpShiftY
+	for _, conflict := range c.conflicts {
+		if conflict {
+			text.Draw(screen, textControlsConflict, fontFaceDebug, controlsLabelX, bottomY-controlsRowHeight, param.ColorFood)
+			break
+		}
+	}
+	text.Draw(screen, textControlsHelp, fontFaceDebug, controlsLabelX, bottomY, param.ColorDebug)
+}
+
+func (c *controlsScene) drawRow(screen *ebiten.Image, action input.Action, rowY int) {
+	var clr color.Color = param.ColorDebug
+	if c.conflicts[action] {
+		clr = param.ColorFood
+	}
+
+	text.Draw(screen, action.Label(), fontFaceMenu, controlsLabelX, rowY, clr)
+
+	binding := input.BindingOf(action)
+	for column := 0; column < controlsNumColumns; column++ {
+		cellX := controlsColumnX + column*controlsColumnWidth
+
+		// Highlight the selected cell
+		if (action == c.selectedAction) && (column == c.selectedColumn) {
+			ebitenutil.DrawRect(screen, float64(cellX), float64(rowY-controlsRowHeight*3/4),
+				controlsColumnWidth-controlsCellPaddingX, controlsRowHeight-controlsCellPaddingX, param.ColorSnake2)
+			if c.waiting {
+				text.Draw(screen, textControlsWaiting, fontFaceMenu, cellX+controlsCellPaddingX, rowY, param.ColorBackground)
+				continue
+			}
+		}
+
+		msg := "-"
+		if column == controlsColumnGamepad {
+			if len(binding.Buttons) > 0 {
+				msg = input.ButtonName(binding.Buttons[0])
+			}
+		} else if column < len(binding.Keys) {
+			msg = binding.Keys[column].String()
+		}
+		text.Draw(screen, msg, fontFaceMenu, cellX+controlsCellPaddingX, rowY, clr)
+	}
+}
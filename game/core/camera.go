@@ -0,0 +1,167 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package core
+
+import (
+	"math"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+// Camera parameters
+const (
+	cameraDeadZone  = 1.0 / 8.0 // Ratio of the screen size the target can move without moving the camera
+	cameraSmoothing = 5.0       // Rate of catching up with the target [1/s]
+)
+
+// Camera is the part of the world drawn on the screen. The world wraps at its bounds, so the view of the
+// camera can cover both sides of a world edge at once.
+type Camera struct {
+	Pos       Vec32  // Top left corner of the view in world coordinates
+	posPrev   Vec32  // Position before the last tick, the frames are drawn between them
+	movedTick uint64 // Tick the camera has moved at
+}
+
+// Cam is the camera the teleportable components are drawn with.
+var Cam Camera
+
+// Reset centers the camera on the target immediately.
+func (c *Camera) Reset(target Vec64) {
+	c.movedTick = 0
+	c.Pos.X = cameraAxisPos(float32(target.X)-float32(param.ScreenWidth)/2.0, param.ScreenWidth, param.WorldWidth)
+	c.Pos.Y = cameraAxisPos(float32(target.Y)-float32(param.ScreenHeight)/2.0, param.ScreenHeight, param.WorldHeight)
+}
+
+// Follow moves the camera smoothly towards the target when it leaves the dead zone at the screen center.
+func (c *Camera) Follow(target Vec64) {
+	if c.movedTick != param.SimTicks { // Keep the position before the first step of the tick
+		c.posPrev = c.Pos
+		c.movedTick = param.SimTicks
+	}
+	smoothing := float32(1.0 - math.Exp(-cameraSmoothing*param.DeltaTime))
+	c.Pos.X = cameraAxisFollow(c.Pos.X, float32(target.X), smoothing, param.ScreenWidth, param.WorldWidth)
+	c.Pos.Y = cameraAxisFollow(c.Pos.Y, float32(target.Y), smoothing, param.ScreenHeight, param.WorldHeight)
+}
+
+func cameraAxisFollow(pos, target, smoothing float32, screenSize, worldSize int) float32 {
+	halfScreen := float32(screenSize) / 2.0
+	deadZone := float32(screenSize) * cameraDeadZone
+
+	delta := wrapDelta(target-(pos+halfScreen), float32(worldSize))
+	if delta > deadZone {
+		pos += (delta - deadZone) * smoothing
+	} else if delta < -deadZone {
+		pos += (delta + deadZone) * smoothing
+	}
+
+	return cameraAxisPos(pos, screenSize, worldSize)
+}
+
+// cameraAxisPos wraps the position into the world. The camera stays still if the world fits the screen.
+func cameraAxisPos(pos float32, screenSize, worldSize int) float32 {
+	if worldSize <= screenSize {
+		return 0
+	}
+	return Wrap(pos, float32(worldSize))
+}
+
+// RenderPos returns the position of the view in the frame. It is interpolated between the last two
+// simulation steps if the camera has moved in the last one.
+func (c *Camera) RenderPos() Vec32 {
+	if c.movedTick != param.StepTick {
+		return c.Pos
+	}
+
+	alpha := float32(param.RenderAlpha)
+	worldWidth, worldHeight := float32(param.WorldWidth), float32(param.WorldHeight)
+	return Vec32{
+		X: cameraAxisPos(c.posPrev.X+wrapDelta(c.Pos.X-c.posPrev.X, worldWidth)*alpha, param.ScreenWidth, param.WorldWidth),
+		Y: cameraAxisPos(c.posPrev.Y+wrapDelta(c.Pos.Y-c.posPrev.Y, worldHeight)*alpha, param.ScreenHeight, param.WorldHeight),
+	}
+}
+
+// ToScreen returns the screen position of the point in the world that is closest to the screen center.
+func (c *Camera) ToScreen(p Vec64) Vec64 {
+	pos := c.RenderPos()
+	if !param.TeleportEnabled {
+		return Vec64{p.X - float64(pos.X), p.Y - float64(pos.Y)}
+	}
+
+	worldWidth, worldHeight := float64(param.WorldWidth), float64(param.WorldHeight)
+	halfScreenWidth, halfScreenHeight := float64(param.ScreenWidth)/2.0, float64(param.ScreenHeight)/2.0
+
+	return Vec64{
+		X: halfScreenWidth + float64(wrapDelta(float32(p.X-float64(pos.X)-halfScreenWidth), float32(worldWidth))),
+		Y: halfScreenHeight + float64(wrapDelta(float32(p.Y-float64(pos.Y)-halfScreenHeight), float32(worldHeight))),
+	}
+}
+
+// views returns the translations from world to screen coordinates of each copy of the rectangle that is
+// visible on the screen. There are more than one copy when the view covers a world edge.
+func (c *Camera) views(rect *RectF32) (translations [4]Vec32, numViews int) {
+	pos := c.RenderPos()
+	translationsX, numX := cameraAxisViews(pos.X, rect.Pos.X, rect.Size.X, param.ScreenWidth, param.WorldWidth)
+	translationsY, numY := cameraAxisViews(pos.Y, rect.Pos.Y, rect.Size.Y, param.ScreenHeight, param.WorldHeight)
+
+	for iX := 0; iX < numX; iX++ {
+		for iY := 0; iY < numY; iY++ {
+			translations[numViews] = Vec32{translationsX[iX], translationsY[iY]}
+			numViews++
+		}
+	}
+
+	return
+}
+
+func cameraAxisViews(camPos, pos, size float32, screenSize, worldSize int) (translations [2]float32, num int) {
+	candidates := [2]float32{-camPos, -camPos + float32(worldSize)}
+	numCandidates := 2
+	if !param.TeleportEnabled || (worldSize <= screenSize) {
+		numCandidates = 1
+	}
+
+	for iCandidate := 0; iCandidate < numCandidates; iCandidate++ {
+		translation := candidates[iCandidate]
+		if (pos+translation < float32(screenSize)) && (pos+size+translation > 0) {
+			translations[num] = translation
+			num++
+		}
+	}
+
+	return
+}
+
+// Wrap returns the value in [0, size), it wraps the coordinates around the world edges.
+func Wrap(value, size float32) float32 {
+	value = float32(math.Mod(float64(value), float64(size)))
+	if value < 0 {
+		value += size
+	}
+	return value
+}
+
+// wrapDelta returns the shortest signed distance in a wrapping axis.
+func wrapDelta(delta, size float32) float32 {
+	delta = Wrap(delta, size)
+	if delta > size/2.0 {
+		delta -= size
+	}
+	return delta
+}
@@ -18,10 +18,14 @@ func NewRect(pos, size Vec32) *RectF32 {
 	return &RectF32{pos, size, Vec32{0, 0}}
 }
 
+// DrawOuterRect draws the visible copies of the rectangle in world coordinates.
 func (r RectF32) DrawOuterRect(dst *ebiten.Image, clr color.Color) {
-	pos64 := r.Pos.To64()
 	size64 := r.Size.To64()
-	ebitenutil.DrawRect(dst, pos64.X, pos64.Y, size64.X, size64.Y, color.RGBA{255, 255, 255, 96})
+	translations, numViews := Cam.views(&r)
+	for iView := 0; iView < numViews; iView++ {
+		pos64 := Vec32{r.Pos.X + translations[iView].X, r.Pos.Y + translations[iView].Y}.To64()
+		ebitenutil.DrawRect(dst, pos64.X, pos64.Y, size64.X, size64.Y, color.RGBA{255, 255, 255, 96})
+	}
 }
 
 func MarkPoint(dst *ebiten.Image, p Vec64, length float64, clr color.Color) {
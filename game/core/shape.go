@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package core
+
+import (
+	"math"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+// Circle is a disk in the world.
+type Circle struct {
+	Center Vec64
+	Radius float64
+}
+
+// Capsule is an axis aligned segment swept by a disk.
+type Capsule struct {
+	Start     Vec64
+	Direction Vec64 // Unit vector on an axis from the start to the end
+	Length    float64
+	Radius    float64
+}
+
+// Contact describes the overlap of two shapes.
+type Contact struct {
+	Point       Vec64   // Middle of the overlap on the line between the shapes
+	Penetration float64 // Depth of the overlap
+}
+
+// Overlaps returns the contact of the circles if they overlap. The nearest copy of the other circle across
+// the world edges is used.
+func (c Circle) Overlaps(other Circle) (Contact, bool) {
+	delta := wrapVec(Vec64{X: other.Center.X - c.Center.X, Y: other.Center.Y - c.Center.Y})
+	return c.contact(delta, other.Radius)
+}
+
+// OverlapsCapsule returns the contact of the circle and the capsule if they overlap. The nearest copies of
+// them across the world edges are used, so a capsule longer than half of the world is handled as well.
+func (c Circle) OverlapsCapsule(capsule Capsule) (Contact, bool) {
+	// Vector from the circle to the start of the capsule
+	delta := wrapVec(Vec64{X: capsule.Start.X - c.Center.X, Y: capsule.Start.Y - c.Center.Y})
+
+	// Position of the circle on the capsule's axis, relative to its start
+	along := -(delta.X*capsule.Direction.X + delta.Y*capsule.Direction.Y)
+	if param.TeleportEnabled {
+		worldSize := float64(param.WorldWidth)
+		if capsule.Direction.X == 0 {
+			worldSize = float64(param.WorldHeight)
+		}
+
+		// The wrapped axis is a circle, find the side of the segment that is nearer.
+		along = math.Mod(along, worldSize)
+		if along < 0 {
+			along += worldSize
+		}
+		if (along > capsule.Length) && (worldSize-along < along-capsule.Length) {
+			along -= worldSize
+		}
+	}
+
+	// Vector from the circle to the nearest point on the segment
+	alongPrev := -(delta.X*capsule.Direction.X + delta.Y*capsule.Direction.Y)
+	nearest := math.Max(0, math.Min(along, capsule.Length))
+	delta.X += capsule.Direction.X * (alongPrev - along + nearest)
+	delta.Y += capsule.Direction.Y * (alongPrev - along + nearest)
+
+	return c.contact(delta, capsule.Radius)
+}
+
+// SegmentDistance returns the distance between the segments of the capsules. The nearest copies across the
+// world edges are used.
+func (c Capsule) SegmentDistance(other Capsule) float64 {
+	minA, maxA := c.bounds()
+	minB, maxB := other.bounds()
+	gapX := intervalGap(minA.X, maxA.X, minB.X, maxB.X, float64(param.WorldWidth))
+	gapY := intervalGap(minA.Y, maxA.Y, minB.Y, maxB.Y, float64(param.WorldHeight))
+	return math.Hypot(gapX, gapY)
+}
+
+// bounds returns the corners of the segment's bounding box, the segment is on one of its sides.
+func (c Capsule) bounds() (min, max Vec64) {
+	end := Vec64{X: c.Start.X + c.Direction.X*c.Length, Y: c.Start.Y + c.Direction.Y*c.Length}
+	return Vec64{X: math.Min(c.Start.X, end.X), Y: math.Min(c.Start.Y, end.Y)},
+		Vec64{X: math.Max(c.Start.X, end.X), Y: math.Max(c.Start.Y, end.Y)}
+}
+
+// intervalGap returns the gap between two intervals on an axis, which wraps with the size if teleport
+// is enabled.
+func intervalGap(minA, maxA, minB, maxB, size float64) float64 {
+	if !param.TeleportEnabled {
+		return math.Max(0, math.Max(minB-maxA, minA-maxB))
+	}
+
+	// Distance from the start of A to the start of B in the positive direction
+	startToStart := math.Mod(minB-minA, size)
+	if startToStart < 0 {
+		startToStart += size
+	}
+	gapAfterA := startToStart - (maxA - minA)
+	gapAfterB := (size - startToStart) - (maxB - minB)
+	if (gapAfterA <= 0) || (gapAfterB <= 0) {
+		return 0
+	}
+	return math.Min(gapAfterA, gapAfterB)
+}
+
+// contact returns the contact with a disk whose center is at the delta from the circle's center.
+func (c Circle) contact(delta Vec64, radius float64) (Contact, bool) {
+	dist := math.Hypot(delta.X, delta.Y)
+	penetration := c.Radius + radius - dist
+	if penetration <= 0 {
+		return Contact{}, false
+	}
+
+	// Direction to the other disk, any direction if the centers are the same
+	normal := Vec64{X: 1, Y: 0}
+	if dist > 0 {
+		normal = Vec64{X: delta.X / dist, Y: delta.Y / dist}
+	}
+
+	toPoint := c.Radius - penetration/2.0
+	point := Vec64{X: c.Center.X + normal.X*toPoint, Y: c.Center.Y + normal.Y*toPoint}
+	if param.TeleportEnabled {
+		point.X = float64(Wrap(float32(point.X), float32(param.WorldWidth)))
+		point.Y = float64(Wrap(float32(point.Y), float32(param.WorldHeight)))
+	}
+
+	return Contact{Point: point, Penetration: penetration}, true
+}
+
+// wrapVec returns the shortest vector in the wrapped world.
+func wrapVec(v Vec64) Vec64 {
+	if !param.TeleportEnabled {
+		return v
+	}
+	return Vec64{
+		X: float64(wrapDelta(float32(v.X), float32(param.WorldWidth))),
+		Y: float64(wrapDelta(float32(v.Y), float32(param.WorldHeight))),
+	}
+}
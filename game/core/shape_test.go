@@ -0,0 +1,318 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+const shapeTolerance = 1e-4
+
+var (
+	dirUp    = Vec64{X: 0, Y: -1}
+	dirDown  = Vec64{X: 0, Y: 1}
+	dirLeft  = Vec64{X: -1, Y: 0}
+	dirRight = Vec64{X: 1, Y: 0}
+)
+
+// setWorld sets the world size and teleport of a test and restores them after it.
+func setWorld(t *testing.T, width, height int, teleport bool) {
+	t.Helper()
+	widthPrev, heightPrev, teleportPrev := param.WorldWidth, param.WorldHeight, param.TeleportEnabled
+	param.WorldWidth, param.WorldHeight, param.TeleportEnabled = width, height, teleport
+	t.Cleanup(func() {
+		param.WorldWidth, param.WorldHeight, param.TeleportEnabled = widthPrev, heightPrev, teleportPrev
+	})
+}
+
+func checkContact(t *testing.T, contact Contact, overlaps, wantOverlaps bool, wantPoint Vec64, wantPenetration float64) {
+	t.Helper()
+	if overlaps != wantOverlaps {
+		t.Fatalf("overlaps = %v, want %v", overlaps, wantOverlaps)
+	}
+	if !overlaps {
+		return
+	}
+	if (math.Abs(contact.Point.X-wantPoint.X) > shapeTolerance) || (math.Abs(contact.Point.Y-wantPoint.Y) > shapeTolerance) {
+		t.Errorf("contact point = %v, want %v", contact.Point, wantPoint)
+	}
+	if math.Abs(contact.Penetration-wantPenetration) > shapeTolerance {
+		t.Errorf("penetration = %v, want %v", contact.Penetration, wantPenetration)
+	}
+}
+
+func TestCircleOverlaps(t *testing.T) {
+	tests := []struct {
+		name            string
+		teleport        bool
+		a, b            Circle
+		wantOverlaps    bool
+		wantPoint       Vec64
+		wantPenetration float64
+	}{
+		{
+			name:         "apart",
+			teleport:     true,
+			a:            Circle{Center: Vec64{X: 100, Y: 100}, Radius: 15},
+			b:            Circle{Center: Vec64{X: 140, Y: 100}, Radius: 15},
+			wantOverlaps: false,
+		},
+		{
+			name:            "across the corner of the world",
+			teleport:        true,
+			a:               Circle{Center: Vec64{X: 5, Y: 5}, Radius: 15},
+			b:               Circle{Center: Vec64{X: 957, Y: 717}, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 1, Y: 1}, // Middle of the overlap on the diagonal
+			wantPenetration: 30 - 8*math.Sqrt2,
+		},
+		{
+			name:         "across the corner without teleport",
+			teleport:     false,
+			a:            Circle{Center: Vec64{X: 5, Y: 5}, Radius: 15},
+			b:            Circle{Center: Vec64{X: 957, Y: 717}, Radius: 15},
+			wantOverlaps: false,
+		},
+		{
+			name:            "same center",
+			teleport:        true,
+			a:               Circle{Center: Vec64{X: 300, Y: 200}, Radius: 15},
+			b:               Circle{Center: Vec64{X: 300, Y: 200}, Radius: 5},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 300 + 15 - 10, Y: 200},
+			wantPenetration: 20,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setWorld(t, 960, 720, test.teleport)
+			contact, overlaps := test.a.Overlaps(test.b)
+			checkContact(t, contact, overlaps, test.wantOverlaps, test.wantPoint, test.wantPenetration)
+		})
+	}
+}
+
+func TestCircleOverlapsCapsule(t *testing.T) {
+	tests := []struct {
+		name            string
+		circle          Circle
+		capsule         Capsule
+		wantOverlaps    bool
+		wantPoint       Vec64
+		wantPenetration float64
+	}{
+		{
+			name:            "capsule split across the edge, circle on the wrapped part",
+			circle:          Circle{Center: Vec64{X: 20, Y: 100}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 950, Y: 100}, Direction: dirRight, Length: 40, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 20, Y: 100},
+			wantPenetration: 30,
+		},
+		{
+			name:            "capsule split across the edge, circle beside the wrapped part",
+			circle:          Circle{Center: Vec64{X: 5, Y: 120}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 950, Y: 100}, Direction: dirRight, Length: 40, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 5, Y: 110},
+			wantPenetration: 10,
+		},
+		{
+			name:            "head exactly on the edge, contact point wraps",
+			circle:          Circle{Center: Vec64{X: 0, Y: 300}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 945, Y: 300}, Direction: dirLeft, Length: 100, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 952.5, Y: 300},
+			wantPenetration: 15,
+		},
+		{
+			name:         "head exactly on the edge, touching only",
+			circle:       Circle{Center: Vec64{X: 0, Y: 300}, Radius: 15},
+			capsule:      Capsule{Start: Vec64{X: 930, Y: 300}, Direction: dirLeft, Length: 100, Radius: 15},
+			wantOverlaps: false,
+		},
+		{
+			name:            "capsule longer than half of the world, near its start",
+			circle:          Circle{Center: Vec64{X: 75, Y: 200}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 100, Y: 200}, Direction: dirRight, Length: 700, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 87.5, Y: 200},
+			wantPenetration: 5,
+		},
+		{
+			name:            "capsule longer than half of the world, near its end",
+			circle:          Circle{Center: Vec64{X: 820, Y: 200}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 100, Y: 200}, Direction: dirRight, Length: 700, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 810, Y: 200},
+			wantPenetration: 10,
+		},
+		{
+			name:         "capsule longer than half of the world, in its gap",
+			circle:       Circle{Center: Vec64{X: 920, Y: 200}, Radius: 15},
+			capsule:      Capsule{Start: Vec64{X: 100, Y: 200}, Direction: dirRight, Length: 700, Radius: 15},
+			wantOverlaps: false,
+		},
+		{
+			name:            "vertical capsule split across the bottom edge",
+			circle:          Circle{Center: Vec64{X: 400, Y: 10}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 410, Y: 700}, Direction: dirDown, Length: 50, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 405, Y: 10},
+			wantPenetration: 20,
+		},
+		{
+			name:            "beside the middle of the segment",
+			circle:          Circle{Center: Vec64{X: 520, Y: 450}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 500, Y: 500}, Direction: dirUp, Length: 100, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 510, Y: 450},
+			wantPenetration: 10,
+		},
+		{
+			name:            "zero-length capsule",
+			circle:          Circle{Center: Vec64{X: 310, Y: 300}, Radius: 15},
+			capsule:         Capsule{Start: Vec64{X: 300, Y: 300}, Direction: dirRight, Length: 0, Radius: 15},
+			wantOverlaps:    true,
+			wantPoint:       Vec64{X: 305, Y: 300},
+			wantPenetration: 20,
+		},
+		{
+			name:         "zero-length capsule apart",
+			circle:       Circle{Center: Vec64{X: 340, Y: 300}, Radius: 15},
+			capsule:      Capsule{Start: Vec64{X: 300, Y: 300}, Direction: dirRight, Length: 0, Radius: 15},
+			wantOverlaps: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setWorld(t, 960, 720, true)
+			contact, overlaps := test.circle.OverlapsCapsule(test.capsule)
+			checkContact(t, contact, overlaps, test.wantOverlaps, test.wantPoint, test.wantPenetration)
+		})
+	}
+}
+
+func TestCapsuleSegmentDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		teleport bool
+		a, b     Capsule
+		want     float64
+	}{
+		{
+			name:     "parallel side by side",
+			teleport: true,
+			a:        Capsule{Start: Vec64{X: 100, Y: 100}, Direction: dirRight, Length: 100},
+			b:        Capsule{Start: Vec64{X: 150, Y: 140}, Direction: dirRight, Length: 100},
+			want:     40,
+		},
+		{
+			name:     "parallel on the same line",
+			teleport: true,
+			a:        Capsule{Start: Vec64{X: 100, Y: 100}, Direction: dirRight, Length: 100},
+			b:        Capsule{Start: Vec64{X: 300, Y: 100}, Direction: dirLeft, Length: 50},
+			want:     50,
+		},
+		{
+			name:     "perpendicular crossing",
+			teleport: true,
+			a:        Capsule{Start: Vec64{X: 100, Y: 100}, Direction: dirRight, Length: 100},
+			b:        Capsule{Start: Vec64{X: 150, Y: 50}, Direction: dirDown, Length: 100},
+			want:     0,
+		},
+		{
+			name:     "perpendicular apart",
+			teleport: true,
+			a:        Capsule{Start: Vec64{X: 100, Y: 100}, Direction: dirRight, Length: 100},
+			b:        Capsule{Start: Vec64{X: 230, Y: 110}, Direction: dirDown, Length: 50},
+			want:     math.Hypot(30, 10),
+		},
+		{
+			name:     "split across the edge",
+			teleport: true,
+			a:        Capsule{Start: Vec64{X: 940, Y: 100}, Direction: dirRight, Length: 40},
+			b:        Capsule{Start: Vec64{X: 30, Y: 100}, Direction: dirRight, Length: 10},
+			want:     10,
+		},
+		{
+			name:     "split across the edge and overlapping",
+			teleport: true,
+			a:        Capsule{Start: Vec64{X: 900, Y: 100}, Direction: dirRight, Length: 100},
+			b:        Capsule{Start: Vec64{X: 20, Y: 90}, Direction: dirDown, Length: 20},
+			want:     0,
+		},
+		{
+			name:     "zero-length segments across the corner",
+			teleport: true,
+			a:        Capsule{Start: Vec64{X: 10, Y: 10}, Direction: dirRight, Length: 0},
+			b:        Capsule{Start: Vec64{X: 950, Y: 710}, Direction: dirUp, Length: 0},
+			want:     math.Hypot(20, 20),
+		},
+		{
+			name:     "zero-length segments without teleport",
+			teleport: false,
+			a:        Capsule{Start: Vec64{X: 10, Y: 10}, Direction: dirRight, Length: 0},
+			b:        Capsule{Start: Vec64{X: 950, Y: 710}, Direction: dirUp, Length: 0},
+			want:     math.Hypot(940, 700),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setWorld(t, 960, 720, test.teleport)
+			if got := test.a.SegmentDistance(test.b); math.Abs(got-test.want) > shapeTolerance {
+				t.Errorf("a to b = %v, want %v", got, test.want)
+			}
+			if got := test.b.SegmentDistance(test.a); math.Abs(got-test.want) > shapeTolerance {
+				t.Errorf("b to a = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestIntervalGap(t *testing.T) {
+	tests := []struct {
+		name                   string
+		minA, maxA, minB, maxB float64
+		want                   float64
+	}{
+		{"apart", 100, 200, 300, 350, 100},
+		{"nearer across the edge", 10, 20, 900, 950, 20},
+		{"touching", 100, 200, 200, 300, 0},
+		{"inside", 100, 400, 200, 300, 0},
+		{"overlapping across the edge", 900, 1000, 10, 20, 0},
+		{"points", 50, 50, 950, 950, 60},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setWorld(t, 960, 720, true)
+			if got := intervalGap(test.minA, test.maxA, test.minB, test.maxB, 960); math.Abs(got-test.want) > shapeTolerance {
+				t.Errorf("intervalGap = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -49,11 +49,13 @@ func (t *TeleComp) split(rect RectF32) {
 
 	rightX := rect.Pos.X + rect.Size.X
 	bottomY := rect.Pos.Y + rect.Size.Y
+	worldWidth := float32(param.WorldWidth)
+	worldHeight := float32(param.WorldHeight)
 
 	if rect.Pos.X < 0 { // left part is off-screen
 		t.split(
 			RectF32{ // teleported left part
-				Pos:       Vec32{rect.Pos.X + param.ScreenWidth, rect.Pos.Y},
+				Pos:       Vec32{rect.Pos.X + worldWidth, rect.Pos.Y},
 				Size:      Vec32{-rect.Pos.X, rect.Size.Y},
 				PosInUnit: Vec32{0, 0},
 			})
@@ -66,18 +68,18 @@ func (t *TeleComp) split(rect RectF32) {
 			})
 
 		return
-	} else if rightX > param.ScreenWidth { // right part is off-screen
+	} else if rightX > worldWidth { // right part is off-screen
 		t.split(
 			RectF32{ // teleported right part
 				Pos:       Vec32{0, rect.Pos.Y},
-				Size:      Vec32{rightX - param.ScreenWidth, rect.Size.Y},
-				PosInUnit: Vec32{param.ScreenWidth - rect.Pos.X, 0},
+				Size:      Vec32{rightX - worldWidth, rect.Size.Y},
+				PosInUnit: Vec32{worldWidth - rect.Pos.X, 0},
 			})
 
 		t.split(
 			RectF32{ // part in the screen
 				Pos:       Vec32{rect.Pos.X, rect.Pos.Y},
-				Size:      Vec32{param.ScreenWidth - rect.Pos.X, rect.Size.Y},
+				Size:      Vec32{worldWidth - rect.Pos.X, rect.Size.Y},
 				PosInUnit: Vec32{0, 0},
 			})
 
@@ -87,7 +89,7 @@ func (t *TeleComp) split(rect RectF32) {
 	if rect.Pos.Y < 0 { // upper part is off-screen
 		t.split(
 			RectF32{ // teleported upper part
-				Pos:       Vec32{rect.Pos.X, param.ScreenHeight + rect.Pos.Y},
+				Pos:       Vec32{rect.Pos.X, worldHeight + rect.Pos.Y},
 				Size:      Vec32{rect.Size.X, -rect.Pos.Y},
 				PosInUnit: Vec32{rect.PosInUnit.X, 0},
 			})
@@ -100,18 +102,18 @@ func (t *TeleComp) split(rect RectF32) {
 			})
 
 		return
-	} else if bottomY > param.ScreenHeight { // bottom part is off-screen
+	} else if bottomY > worldHeight { // bottom part is off-screen
 		t.split(
 			RectF32{ // teleported bottom part
 				Pos:       Vec32{rect.Pos.X, 0},
-				Size:      Vec32{rect.Size.X, bottomY - param.ScreenHeight},
-				PosInUnit: Vec32{rect.PosInUnit.X, param.ScreenHeight - rect.Pos.Y},
+				Size:      Vec32{rect.Size.X, bottomY - worldHeight},
+				PosInUnit: Vec32{rect.PosInUnit.X, worldHeight - rect.Pos.Y},
 			})
 
 		t.split(
 			RectF32{ // part in the screen
 				Pos:       Vec32{rect.Pos.X, rect.Pos.Y},
-				Size:      Vec32{rect.Size.X, param.ScreenHeight - rect.Pos.Y},
+				Size:      Vec32{rect.Size.X, worldHeight - rect.Pos.Y},
 				PosInUnit: Vec32{rect.PosInUnit.X, 0},
 			})
 
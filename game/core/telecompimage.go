@@ -22,6 +22,7 @@ package core
 import (
 	"image/color"
 
+	"github.com/anilkonac/snake-ebiten/game/param"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -34,7 +35,9 @@ func init() {
 // TeleCompImage is a TeleComp with drawing options for each rectangle to be used in the DrawImage method.
 type TeleCompImage struct {
 	TeleComp
-	DrawOpts [4]ebiten.DrawImageOptions
+	DrawOpts     [4]ebiten.DrawImageOptions
+	clr          *color.RGBA
+	themeVersion uint32
 }
 
 func (t *TeleCompImage) Update(pureRect *RectF32) {
@@ -53,7 +56,9 @@ func (t *TeleCompImage) Update(pureRect *RectF32) {
 	}
 }
 
-func (t *TeleCompImage) SetColor(clr color.Color) {
+func (t *TeleCompImage) SetColor(clr *color.RGBA) {
+	t.clr = clr
+	t.themeVersion = param.ThemeVersion
 	for iDrawOpt := 0; iDrawOpt < 4; iDrawOpt++ {
 		drawOpt := &t.DrawOpts[iDrawOpt]
 		drawOpt.ColorM.Reset()
@@ -62,8 +67,19 @@ func (t *TeleCompImage) SetColor(clr color.Color) {
 }
 
 func (t *TeleCompImage) Draw(dst *ebiten.Image) {
+	// Read the color again if the theme has changed since it was set.
+	if (t.clr != nil) && (t.themeVersion != param.ThemeVersion) {
+		t.SetColor(t.clr)
+	}
+
+	// Draw the visible copies of the rectangles on the screen
+	var drawOpt ebiten.DrawImageOptions
 	for iRect := uint8(0); iRect < t.NumRects; iRect++ {
-		drawOpt := &t.DrawOpts[iRect]
-		dst.DrawImage(imagePixel, drawOpt)
+		translations, numViews := Cam.views(&t.Rects[iRect])
+		for iView := 0; iView < numViews; iView++ {
+			drawOpt = t.DrawOpts[iRect]
+			drawOpt.GeoM.Translate(float64(translations[iView].X), float64(translations[iView].Y))
+			dst.DrawImage(imagePixel, &drawOpt)
+		}
 	}
 }
@@ -22,15 +22,19 @@ package core
 import (
 	"image/color"
 
+	"github.com/anilkonac/snake-ebiten/game/param"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// Each of the 4 rectangles can be seen 4 times at most when the camera view covers the world corners.
+const maxViewRects = 16
+
 var (
-	indices [24]uint16
+	indices [maxViewRects * 6]uint16
 )
 
 func init() {
-	for iRect := uint16(0); iRect < 4; iRect++ {
+	for iRect := uint16(0); iRect < maxViewRects; iRect++ {
 		indices[iRect*6] = iRect * 4
 		indices[iRect*6+1] = iRect*4 + 2
 		indices[iRect*6+2] = iRect*4 + 1
@@ -44,11 +48,17 @@ func init() {
 // TeleCompTriang is TeleComp with triangulation information for the DrawTriangles and DrawTriangleShader methods
 type TeleCompTriang struct {
 	TeleComp
-	vertices [16]ebiten.Vertex
-	color    [4]float32
+	ScreenSpace  bool // The rectangles are in screen coordinates, so the camera is not applied.
+	vertices     [16]ebiten.Vertex
+	viewVertices [maxViewRects * 4]ebiten.Vertex
+	color        [4]float32
+	clr          *color.RGBA
+	themeVersion uint32
 }
 
 func (t *TeleCompTriang) SetColor(clr *color.RGBA) {
+	t.clr = clr
+	t.themeVersion = param.ThemeVersion
 	t.color = [4]float32{float32(clr.R) / 255.0, float32(clr.G) / 255.0, float32(clr.B) / 255.0, float32(clr.A) / 255.0}
 }
 
@@ -114,5 +124,30 @@ func (t *TeleCompTriang) updateVertices() {
 }
 
 func (t *TeleCompTriang) Triangles() ([]ebiten.Vertex, []uint16) {
-	return t.vertices[:t.NumRects*4], indices[:t.NumRects*6]
+	// Read the color again if the theme has changed since it was set.
+	if (t.clr != nil) && (t.themeVersion != param.ThemeVersion) {
+		t.SetColor(t.clr)
+		t.updateVertices()
+	}
+
+	if t.ScreenSpace {
+		return t.vertices[:t.NumRects*4], indices[:t.NumRects*6]
+	}
+
+	// Translate the visible copies of the rectangles to the screen and cull the rest.
+	var numViewRects int
+	for iRect := uint8(0); iRect < t.NumRects; iRect++ {
+		translations, numViews := Cam.views(&t.Rects[iRect])
+		for iView := 0; iView < numViews; iView++ {
+			for iVertex := 0; iVertex < 4; iVertex++ {
+				vertex := t.vertices[int(iRect)*4+iVertex]
+				vertex.DstX += translations[iView].X
+				vertex.DstY += translations[iView].Y
+				t.viewVertices[numViewRects*4+iVertex] = vertex
+			}
+			numViewRects++
+		}
+	}
+
+	return t.viewVertices[:numViewRects*4], indices[:numViewRects*6]
 }
@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Display parameters
+const (
+	settingsDisplay   = "display"
+	windowScreenRatio = 0.9 // Maximum ratio of the window size to the monitor size
+)
+
+// aspectRatio is a screen size preset. The widths are at least the default width so that the menus fit.
+type aspectRatio struct {
+	name          string
+	width, height int
+}
+
+var aspectRatios = []aspectRatio{
+	{"4:3", param.ScreenWidthDefault, param.ScreenHeightDefault},
+	{"16:10", 1152, 720},
+	{"16:9", 1280, 720},
+	{"21:9", 1680, 720},
+	{"1:1", 960, 960},
+}
+
+// World size is the screen size multiplied by one of these scales.
+var worldScales = []int{1, 2, 3, 4}
+
+type displaySettings struct {
+	AspectRatio  string `json:"aspectRatio"`
+	WorldScale   int    `json:"worldScale"`
+	Fullscreen   bool   `json:"fullscreen"`
+	DustTrail    bool   `json:"dustTrail"`    // Particles are left behind the tail
+	RoundedTurns bool   `json:"roundedTurns"` // Skinned snakes are drawn with arcs at the turns
+	SimRate      int    `json:"simRate"`      // Simulation ticks per second
+}
+
+var display = displaySettings{
+	AspectRatio:  aspectRatios[0].name,
+	WorldScale:   1,
+	RoundedTurns: true,
+	SimRate:      param.SimRateDefault,
+}
+
+func init() {
+	if err := settings.Load(settingsDisplay, &display); err != nil {
+		fmt.Println("Could not load the display settings:", err)
+	}
+	applyDisplaySettings()
+}
+
+// InitWindow sets up the window before the game runs.
+func InitWindow() {
+	ebiten.SetWindowTitle("Ssnake")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	ebiten.SetWindowSize(windowSize())
+	ebiten.SetFullscreen(display.Fullscreen)
+}
+
+// windowSize returns the largest integer scale of the screen that fits the monitor, or a downscaled size
+// of it if the screen is larger than the monitor. The sizes are in device-independent pixels.
+func windowSize() (int, int) {
+	monitorWidth, monitorHeight := ebiten.ScreenSizeInFullscreen()
+	if monitorWidth <= 0 || monitorHeight <= 0 {
+		return param.ScreenWidth, param.ScreenHeight
+	}
+
+	scale := math.Min(
+		float64(monitorWidth)*windowScreenRatio/float64(param.ScreenWidth),
+		float64(monitorHeight)*windowScreenRatio/float64(param.ScreenHeight))
+	if scale >= 1 {
+		scale = math.Floor(scale)
+	}
+
+	return int(float64(param.ScreenWidth) * scale), int(float64(param.ScreenHeight) * scale)
+}
+
+func findAspectRatio(name string) int {
+	for iRatio, ratio := range aspectRatios {
+		if ratio.name == name {
+			return iRatio
+		}
+	}
+	return 0
+}
+
+func findWorldScale(scale int) int {
+	for iScale, worldScale := range worldScales {
+		if worldScale == scale {
+			return iScale
+		}
+	}
+	return 0
+}
+
+// applyDisplaySettings sets the screen and world sizes. Unknown values fall back to the first presets.
+func applyDisplaySettings() {
+	ratio := aspectRatios[findAspectRatio(display.AspectRatio)]
+	display.AspectRatio = ratio.name
+	display.WorldScale = worldScales[findWorldScale(display.WorldScale)]
+
+	param.ScreenWidth = ratio.width
+	param.ScreenHeight = ratio.height
+	param.WorldWidth = ratio.width * display.WorldScale
+	param.WorldHeight = ratio.height * display.WorldScale
+	snake.RoundedTurns = display.RoundedTurns
+	applySimRate()
+}
+
+// selectAspectRatio changes the screen size by the offset in the presets and saves it.
+func selectAspectRatio(offset int) {
+	display.AspectRatio = aspectRatios[wrapIndex(findAspectRatio(display.AspectRatio)+offset, len(aspectRatios))].name
+	applyDisplaySettings()
+	if !ebiten.IsFullscreen() {
+		ebiten.SetWindowSize(windowSize())
+	}
+	saveDisplaySettings()
+}
+
+// selectWorldScale changes the world size by the offset in the presets and saves it.
+func selectWorldScale(offset int) {
+	display.WorldScale = worldScales[wrapIndex(findWorldScale(display.WorldScale)+offset, len(worldScales))]
+	applyDisplaySettings()
+	saveDisplaySettings()
+}
+
+func aspectRatioName() string {
+	return fmt.Sprintf("%s (%dx%d)", display.AspectRatio, param.ScreenWidth, param.ScreenHeight)
+}
+
+func worldScaleName() string {
+	return fmt.Sprintf("%dx (%dx%d)", display.WorldScale, param.WorldWidth, param.WorldHeight)
+}
+
+func toggleFullscreen() {
+	display.Fullscreen = !ebiten.IsFullscreen()
+	ebiten.SetFullscreen(display.Fullscreen)
+	saveDisplaySettings()
+}
+
+func toggleDustTrail() {
+	display.DustTrail = !display.DustTrail
+	saveDisplaySettings()
+}
+
+func toggleRoundedTurns() {
+	display.RoundedTurns = !display.RoundedTurns
+	snake.RoundedTurns = display.RoundedTurns
+	saveDisplaySettings()
+}
+
+func saveDisplaySettings() {
+	if err := settings.Save(settingsDisplay, &display); err != nil {
+		fmt.Println("Could not save the display settings:", err)
+	}
+}
+
+func wrapIndex(index, length int) int {
+	return (index%length + length) % length
+}
@@ -20,8 +20,11 @@ along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
 package game
 
 import (
+	"github.com/anilkonac/snake-ebiten/game/input"
 	"github.com/anilkonac/snake-ebiten/game/object/snake"
 	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/skin"
+	"github.com/anilkonac/snake-ebiten/game/sound"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
@@ -37,7 +40,9 @@ type Game struct {
 }
 
 func NewGame() *Game {
-	playerSnake := snake.NewSnakeRandDirLoc(param.SnakeLength, param.SnakeSpeedInitial, &param.ColorSnake1)
+	ebiten.SetTPS(ebiten.SyncWithFPS) // The sim clock takes the ticks
+	clock.reset()
+	playerSnake := newPlayerSnake()
 
 	return &Game{
 		curScene:    newTitleScene(playerSnake),
@@ -45,28 +50,72 @@ func NewGame() *Game {
 	}
 }
 
-// Update is called every tick (1/60 [s] by default).
+func newPlayerSnake() *snake.Snake {
+	playerSnake := snake.NewSnakeRandDirLoc(param.SnakeLength, param.SnakeSpeedInitial, &param.ColorSnake1)
+	playerSnake.Skin = skin.Player()
+	return playerSnake
+}
+
+// Update is called every frame, it takes the ticks of the simulation that the frame covers.
 func (g *Game) Update() error {
+	input.Update()
+	for numTicks := clock.advance(); numTicks > 0; numTicks-- {
+		g.tick()
+		input.EndTick()
+	}
+	return nil
+}
+
+// tick is one step of the simulation (1/120 [s] by default).
+func (g *Game) tick() {
+	clock.step()
+	sound.Update()
+
+	if input.IsActionJustPressed(input.ActionToggleFullscreen) {
+		toggleFullscreen()
+	}
+
+	updatePostEffects(g.curScene)
+
 	if g.curScene.update() {
-		switch g.curScene.(type) {
+		switch scene := g.curScene.(type) {
 		case *titleScene:
-			g.curScene = newGameScene(g.playerSnake)
+			if scene.menuScene != nil {
+				g.curScene = scene.menuScene
+			} else {
+				g.curScene = newGameScene(g.playerSnake, scene.mode)
+			}
+		case *optionsScene:
+			if scene.worldChanged() {
+				// The old snake may lie outside of the new world.
+				g.playerSnake = newPlayerSnake()
+			}
+			g.curScene = newTitleScene(g.playerSnake)
+		case *controlsScene:
+			g.curScene = newTitleScene(g.playerSnake)
+		case *benchScene, *achievementsScene, *highScoresScene:
+			g.curScene = newTitleScene(g.playerSnake)
+		case *skinsScene:
+			g.playerSnake.Skin = skin.Player()
+			g.curScene = newTitleScene(g.playerSnake)
+		case *gameScene:
+			g.playerSnake = newPlayerSnake()
+			g.curScene = newTitleScene(g.playerSnake)
 		}
 	}
-
-	return nil
 }
 
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
+// The moving objects are drawn between their last two simulation states.
 func (g *Game) Draw(screen *ebiten.Image) {
-	g.curScene.draw(screen)
+	param.RenderAlpha = clock.alpha()
+	param.StepTick = param.SimTicks
+	drawWithPostEffects(screen, g.curScene.draw)
 }
 
 // Layout takes the outside size (e.g., the window size) and returns the (logical) screen size.
+// The logical screen is the play field, Ebitengine scales it to the window with letterboxing. The device
+// scale factor is not applied, so the frame is upscaled rather than rendered sharper on HiDPI monitors.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return param.ScreenWidth, param.ScreenHeight
 }
-
-func ScreenSize() (int, int) {
-	return param.ScreenWidth, param.ScreenHeight
-}
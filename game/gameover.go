@@ -0,0 +1,256 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/anilkonac/snake-ebiten/game/shader"
+	"github.com/anilkonac/snake-ebiten/game/telemetry"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Game over parameters
+const (
+	settingsRecords    = "records"
+	deathAnimTime      = 1.2 // seconds
+	deathRingSize      = 128
+	deathRingRadiusMax = 110.0
+	deathMarkRadius    = 8.0
+	deathMarkBlinkRate = 6.0 // Hz
+)
+
+// Summary panel layout parameters
+const (
+	textGameOver      = "Game Over"
+	textSummaryHelp   = "%s: Restart   %s: Menu   %s: Save replay"
+	textNewBest       = "New best!"
+	summaryWidth      = 640
+	summaryHeight     = 552
+	summaryAlpha      = 224
+	summaryPaddingX   = 40
+	summaryTitleY     = 50
+	summaryRowsY      = 120
+	summaryRowHeight  = 44
+	summaryHelpShiftY = 20
+)
+
+// records are the personal bests saved in the settings file. The best scores are kept in the high-score
+// tables of the modes.
+type records struct {
+	BestLength float64 `json:"bestLength"`
+	BestTime   float64 `json:"bestTime"` // seconds
+}
+
+var (
+	personalBest   records
+	imageDeathRing = ebiten.NewImage(deathRingSize, deathRingSize)
+)
+
+func init() {
+	imageDeathRing.DrawRectShader(deathRingSize, deathRingSize, &shader.Circle, &ebiten.DrawRectShaderOptions{
+		Uniforms: map[string]interface{}{
+			"Radius": float32(deathRingSize / 2.0),
+		},
+	})
+
+	if err := settings.Load(settingsRecords, &personalBest); err != nil {
+		fmt.Println("Could not load the records:", err)
+	}
+}
+
+// gameOverSummary holds the results of a finished game.
+type gameOverSummary struct {
+	score     int
+	streak    int // Longest food combo
+	foodEaten int
+	maxLength float64
+	timeAlive float64 // seconds
+	bestScore int     // Best score of the mode before this game
+	newBest   bool
+	rank      int    // Rank in the high-score table of the mode, 0 if it is not in the table
+	message   string // Result of the last panel action
+}
+
+// endGame ends the game by the rules of the mode. The summary is shown without the death animation.
+func (g *gameScene) endGame() {
+	g.gameOver = true
+	g.ended = true
+	g.timeAfterGameOver = deathAnimTime
+	g.finishGame()
+}
+
+// timeAlive returns the seconds played in the game.
+func (g *gameScene) timeAlive() float64 {
+	return float64(g.ticks) * param.DeltaTime
+}
+
+// finishGame fills the summary of the game and updates the personal bests. The bests are not updated if
+// the game is changed by the console.
+func (g *gameScene) finishGame() {
+	g.summary = gameOverSummary{
+		score:     g.scoring.score,
+		streak:    g.scoring.bestStreak,
+		foodEaten: int(g.snake.FoodEaten),
+		maxLength: g.maxLength,
+		timeAlive: g.timeAlive(),
+		bestScore: bestScore(g.mode),
+	}
+	g.replay.finish(g.ticks, g.summary.score)
+	if g.cheated {
+		g.summary.message = "Not recorded, the console has been used"
+	} else {
+		g.recordBests()
+	}
+
+	g.publishEvent(telemetry.Event{Kind: telemetry.KindGameOver, Score: g.summary.score})
+	flushTelemetry()
+}
+
+// recordBests adds the game to the high scores and updates the personal bests.
+func (g *gameScene) recordBests() {
+	g.summary.newBest = g.summary.score > g.summary.bestScore
+	g.summary.rank = addHighScore(g.mode, highScore{Score: g.summary.score, TimeAlive: g.summary.timeAlive, Date: time.Now()})
+
+	personalBest.BestLength = math.Max(personalBest.BestLength, g.summary.maxLength)
+	personalBest.BestTime = math.Max(personalBest.BestTime, g.summary.timeAlive)
+	if err := settings.Save(settingsRecords, &personalBest); err != nil {
+		fmt.Println("Could not save the records:", err)
+	}
+}
+
+// updateGameOver plays the death animation and then handles the summary panel actions.
+// It returns true when the player goes back to the menu.
+func (g *gameScene) updateGameOver() bool {
+	if g.timeAfterGameOver < deathAnimTime {
+		return false
+	}
+
+	switch {
+	case input.IsActionJustPressed(input.ActionRestart):
+		g.restart()
+	case input.IsActionJustPressed(input.ActionMenuBack):
+		return true
+	case input.IsActionJustPressed(input.ActionSaveReplay):
+		if location, err := g.replay.save(); err != nil {
+			g.summary.message = "Could not save the replay: " + err.Error()
+		} else {
+			g.summary.message = "Replay saved to " + location
+		}
+	}
+
+	return false
+}
+
+// drawDeath highlights the collision point with a fading shock wave and a blinking mark.
+func (g *gameScene) drawDeath(screen *ebiten.Image) {
+	pos := c.Cam.ToScreen(g.collisionPoint.To64())
+
+	if progress := g.timeAfterGameOver / deathAnimTime; progress < 1 {
+		easeOut := 1 - (1-progress)*(1-progress)
+		radius := deathRingRadiusMax * float64(easeOut)
+		var drawOpts ebiten.DrawImageOptions
+		drawOpts.GeoM.Scale(2*radius/deathRingSize, 2*radius/deathRingSize)
+		drawOpts.GeoM.Translate(pos.X-radius, pos.Y-radius)
+		drawOpts.ColorM.ScaleWithColor(param.ColorFood)
+		drawOpts.ColorM.Scale(1, 1, 1, float64(1-progress)*0.5)
+		screen.DrawImage(imageDeathRing, &drawOpts)
+	}
+
+	if math.Sin(float64(g.timeAfterGameOver)*2*math.Pi*deathMarkBlinkRate) > -0.5 {
+		var drawOpts ebiten.DrawImageOptions
+		drawOpts.GeoM.Scale(2*deathMarkRadius/deathRingSize, 2*deathMarkRadius/deathRingSize)
+		drawOpts.GeoM.Translate(pos.X-deathMarkRadius, pos.Y-deathMarkRadius)
+		drawOpts.ColorM.ScaleWithColor(param.ColorFood)
+		screen.DrawImage(imageDeathRing, &drawOpts)
+	}
+}
+
+// drawSummary draws the results panel after the death animation.
+func (g *gameScene) drawSummary(screen *ebiten.Image) {
+	if g.timeAfterGameOver < deathAnimTime {
+		return
+	}
+
+	panelX := (param.ScreenWidth - summaryWidth) / 2
+	panelY := (param.ScreenHeight - summaryHeight) / 2
+	ebitenutil.DrawRect(screen, float64(panelX), float64(panelY), summaryWidth, summaryHeight, fadeColor(param.ColorBackground, summaryAlpha))
+
+	// Draw title
+	title := textGameOver
+	if g.ended && (g.mode.textEnd != "") {
+		title = g.mode.textEnd
+	}
+	boundTitle := text.BoundString(param.FontFaceScore, title)
+	text.Draw(screen, title, param.FontFaceScore,
+		panelX+(summaryWidth-boundTitle.Size().X)/2-boundTitle.Min.X, panelY+summaryTitleY, param.ColorScore)
+
+	// Draw results
+	summary := &g.summary
+	bestScore := fmt.Sprintf("%05d", summary.bestScore)
+	if summary.newBest {
+		bestScore = textNewBest
+	}
+	rank := "-"
+	if summary.rank > 0 {
+		rank = fmt.Sprintf("#%d", summary.rank)
+	}
+	rows := [...][2]string{
+		{"Mode", g.mode.name},
+		{"Score", fmt.Sprintf("%05d", summary.score)},
+		{"Food eaten", fmt.Sprint(summary.foodEaten)},
+		{"Best streak", fmt.Sprint(summary.streak)},
+		{"Max length", fmt.Sprintf("%.0f", summary.maxLength)},
+		{"Time alive", formatDuration(summary.timeAlive)},
+		{"Personal best", bestScore},
+		{"Rank", rank},
+	}
+	for iRow, row := range rows {
+		rowY := panelY + summaryRowsY + iRow*summaryRowHeight
+		text.Draw(screen, row[0], fontFaceMenu, panelX+summaryPaddingX, rowY, param.ColorDebug)
+
+		boundValue := text.BoundString(fontFaceMenu, row[1])
+		text.Draw(screen, row[1], fontFaceMenu, panelX+summaryWidth-summaryPaddingX-boundValue.Max.X, rowY, param.ColorSnake1)
+	}
+
+	// Draw the action result and help text
+	bottomY := panelY + summaryHeight - summaryHelpShiftY
+	if summary.message != "" {
+		text.Draw(screen, summary.message, fontFaceDebug, panelX+summaryPaddingX/2, bottomY-summaryRowHeight, param.ColorDebug)
+	}
+	help := fmt.Sprintf(textSummaryHelp, input.KeyLabel(input.ActionRestart), input.KeyLabel(input.ActionMenuBack),
+		input.KeyLabel(input.ActionSaveReplay))
+	boundHelp := text.BoundString(fontFaceDebug, help)
+	text.Draw(screen, help, fontFaceDebug, panelX+(summaryWidth-boundHelp.Size().X)/2, bottomY, param.ColorDebug)
+}
+
+// formatDuration formats the seconds as minutes:seconds.tenths.
+func formatDuration(seconds float64) string {
+	minutes := int(seconds) / 60
+	return fmt.Sprintf("%d:%04.1f", minutes, seconds-float64(minutes*60))
+}
@@ -24,92 +24,364 @@ import (
 	"math"
 
 	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/input"
 	"github.com/anilkonac/snake-ebiten/game/object"
 	s "github.com/anilkonac/snake-ebiten/game/object/snake"
 	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/anilkonac/snake-ebiten/game/skin"
+	"github.com/anilkonac/snake-ebiten/game/sound"
+	"github.com/anilkonac/snake-ebiten/game/telemetry"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/text"
 )
 
 // Game scene constants
 const (
-	restartTime      = 1.5 // seconds
-	snakeHeadCenterX = param.HalfScreenWidth
-	snakeHeadCenterY = param.HalfScreenHeight
+	settingsTurnPolicy = "turnPolicy"
 )
 
+// Toast parameters
+const toastShiftY = 16 // Distance of the first toast from the top of the screen
+
+// Time scale debug parameters
+const (
+	timeScaleSlow = 0.25
+	timeScaleFast = 4.0
+	timeScaleMax  = 8.0 // Upper limit of the time scale set from the console
+)
+
+// Turn queue debug overlay parameters
+const (
+	turnDebugShiftX    = 10
+	turnDebugShiftY    = 60
+	turnDebugLineSpace = 22
+	turnDebugBarWidth  = 200
+	turnDebugBarHeight = 10
+)
+
+var turnPolicy = s.DefaultTurnPolicy
+
+func init() {
+	if err := settings.Load(settingsTurnPolicy, &turnPolicy); err != nil {
+		fmt.Println("Could not load the turn policy:", err)
+	}
+}
+
 type gameScene struct {
 	snake             *s.Snake
 	food              *object.Food
 	gameOver          bool
 	paused            bool
+	adaptiveMusic     bool // Adaptive music is played instead of the game playlist
 	timeAfterGameOver float32
 	scoreAnimList     []*object.ScoreAnim
+	particles         *object.Particles
+	ticks             int // Ticks played until the game is over
+	maxLength         float64
+	collisionPoint    c.Vec32
+	summary           gameOverSummary
+	replay            *replay
+	broadphase        *object.Broadphase
+	candidates        []object.Collidable // Scratch buffer of the broadphase queries
+	queryBounds       c.TeleComp          // Bounds of the last broadphase query split at the world edges
+	stepDebt          float64             // Fraction of a simulation step left over at the time scale
+	stepRequested     bool                // A single step is taken in the next tick while paused
+	stepTick          uint64              // Tick of the last simulation step
+	timeTick          uint64              // Last tick the game time has advanced in
+	console           console
+	toasts            []*object.Toast
+	scoring           scoring
+	mode              *gameMode
+	ended             bool    // The game is ended by the mode, not by a collision
+	gameNumber        int     // Number of the game in the telemetry session
+	hitUnit           *s.Unit // Unit the head has hit
+	godMode           bool    // The snake does not collide, toggled by the console
+	cheated           bool    // A console command has changed the game, so it is not recorded
 }
 
-func newGameScene(snake *s.Snake) *gameScene {
+func newGameScene(snake *s.Snake, mode modeID) *gameScene {
 	param.TeleportEnabled = true
 	s.MouthEnabled = true
+	snake.TurnPolicy = &turnPolicy
+
+	scene := &gameScene{
+		snake:         snake,
+		food:          object.NewFoodRandLoc(),
+		adaptiveMusic: sound.Adaptive() && (musicAdaptive != nil),
+		particles:     object.NewParticles(),
+		broadphase:    object.NewBroadphase(),
+		replay:        newReplay(snake, &gameModes[mode]),
+		scoring:       newScoring(),
+		mode:          &gameModes[mode],
+	}
+	scene.setObstacles()
+	scene.startMode()
+	scene.registerCollidables()
+	scene.startTelemetry()
 
-	return &gameScene{
-		snake: snake,
-		food:  object.NewFoodRandLoc(),
+	if scene.adaptiveMusic {
+		sound.PlayMusic(musicAdaptive)
+	} else {
+		sound.PlayPlaylist(playlistGame)
 	}
+
+	return scene
 }
 
 func (g *gameScene) restart() {
 	*g = gameScene{
-		snake: s.NewSnakeRandDir(c.Vec64{X: snakeHeadCenterX, Y: snakeHeadCenterY}, param.SnakeLength, param.SnakeSpeedInitial, &param.ColorSnake1),
-		food:  object.NewFoodRandLoc(),
+		snake:         s.NewSnakeRandDir(c.Vec64{X: float64(param.WorldWidth) / 2.0, Y: float64(param.WorldHeight) / 2.0}, param.SnakeLength, param.SnakeSpeedInitial, &param.ColorSnake1),
+		food:          object.NewFoodRandLoc(),
+		adaptiveMusic: g.adaptiveMusic,
+		particles:     g.particles,
+		broadphase:    g.broadphase,
+		candidates:    g.candidates,
+		console:       g.console,
+		toasts:        g.toasts,
+		scoring:       newScoring(),
+		mode:          g.mode,
 	}
+	g.snake.TurnPolicy = &turnPolicy
+	g.snake.Skin = skin.Player()
+	g.setObstacles()
+	g.startMode()
+	g.registerCollidables()
+	g.replay = newReplay(g.snake, g.mode)
+	c.Cam.Reset(g.snake.UnitHead.HeadCenter)
+	g.startTelemetry()
 }
 
 func (g *gameScene) update() bool {
+	if g.console.update(g) {
+		return false // The game waits while the console is open
+	}
+
 	g.handleSettingsInputs()
+	updateAdaptiveMusic(g)
+	g.updateToasts()
 
-	if g.paused {
+	if g.paused && !g.stepRequested {
 		return false
 	}
+	numSteps := g.simSteps()
+	g.timeTick = param.SimTicks
+	if numSteps > 0 {
+		g.stepTick = param.SimTicks
+	}
 
 	if g.gameOver {
-		g.timeAfterGameOver += param.DeltaTime
-		if g.timeAfterGameOver >= restartTime {
-			g.restart()
+		for iStep := 0; iStep < numSteps; iStep++ {
+			g.particles.Update()
+			g.timeAfterGameOver += float32(param.DeltaTime)
 		}
+		return g.updateGameOver()
+	}
+
+	if g.mode.endByKey && input.IsActionJustPressed(input.ActionMenuBack) {
+		g.endGame()
 		return false
 	}
 
+	// The inputs are handled every tick, so they are not lost or repeated when the time is scaled.
 	g.handleInput()
+	for iStep := 0; (iStep < numSteps) && !g.gameOver; iStep++ {
+		g.step()
+	}
+
+	return false
+}
+
+// simSteps returns how many simulation steps are taken in this tick. The steps are taken at the time
+// scale, so a step can span several ticks or several steps can be taken in a tick. While paused, only
+// the requested single step is taken. A game played at another time scale is marked as cheated.
+func (g *gameScene) simSteps() int {
+	if g.paused {
+		g.stepRequested = false
+		return 1
+	}
+
+	if param.TimeScale != 1 && !g.gameOver && !g.cheated {
+		g.cheat("timeScale")
+	}
+	g.stepDebt += param.TimeScale
+	numSteps := math.Floor(g.stepDebt)
+	g.stepDebt -= numSteps
+	return int(numSteps)
+}
+
+// setRenderAlpha places the frame between the last two steps of the game. In slow motion, a step spans
+// several ticks, so the frame is placed by the scaled time since the step. The objects are not
+// interpolated while the game time stands still.
+func (g *gameScene) setRenderAlpha() {
+	param.StepTick = g.stepTick
+	if g.paused || (g.timeTick != param.SimTicks) {
+		param.RenderAlpha = 1
+	} else if param.TimeScale < 1 {
+		param.RenderAlpha = math.Min(g.stepDebt+param.RenderAlpha*param.TimeScale, 1)
+	}
+}
+
+// step advances the game by a fixed time step.
+func (g *gameScene) step() {
+	g.particles.Update()
+	g.scoring.update()
+	g.ticks++
+	if g.mode.step != nil {
+		if g.mode.step(g); g.gameOver {
+			return
+		}
+	}
 
 	distToFood := g.calcFoodDist()
+	headPrev := g.snake.UnitHead.HeadCenter
 	g.snake.Update(distToFood)
+	g.checkWrap(headPrev)
+	g.maxLength = math.Max(g.maxLength, g.snake.Length())
+	c.Cam.Follow(g.snake.UnitHead.HeadCenter)
+	if display.DustTrail {
+		g.particles.EmitDust(g.snake.TailCenter(), g.snake.Color())
+	}
+	g.registerCollidables()
 	g.checkIntersection()
+	g.checkNearMiss()
 	g.updateScoreAnims()
 	g.checkFood(distToFood)
+}
 
-	return false
+// checkWrap publishes a wrap event if the head has teleported to the other side of the world.
+func (g *gameScene) checkWrap(headPrev c.Vec64) {
+	head := g.snake.UnitHead.HeadCenter
+	if (math.Abs(head.X-headPrev.X) > float64(param.WorldWidth)/2.0) ||
+		(math.Abs(head.Y-headPrev.Y) > float64(param.WorldHeight)/2.0) {
+		g.publishEvent(telemetry.Event{Kind: telemetry.KindWrap})
+		g.scoring.wrapped()
+	}
+}
+
+// checkNearMiss scores the head passing close to the body. There is no bonus while the snake passes
+// through itself, since the head could enter and leave its body without a risk.
+func (g *gameScene) checkNearMiss() {
+	if g.gameOver {
+		return
+	}
+	if g.snake.Obstacles == nil {
+		g.scoring.resetNearMiss()
+		return
+	}
+	if points := g.scoring.checkNearMiss(g.nearBody()); points > 0 {
+		g.triggerScoreAnim(points)
+	}
+}
+
+// nearBody returns whether the gap between the head and the body that it can hit is less than the near
+// miss gap, and whether the head overlaps it. Only the units near the head in the broadphase are tested.
+func (g *gameScene) nearBody() (near, overlaps bool) {
+	head := g.snake.HeadCircle(param.RadiusSnake + nearMissGap)
+	extent := head.Radius + param.RadiusSnake
+	g.queryCandidates(&c.RectF32{
+		Pos:  c.Vec32{X: float32(head.Center.X - extent), Y: float32(head.Center.Y - extent)},
+		Size: c.Vec32{X: float32(2.0 * extent), Y: float32(2.0 * extent)},
+	})
+
+	for _, candidate := range g.candidates {
+		capsule, ok := g.snake.BodyCapsule(candidate.(*s.Unit))
+		if !ok {
+			continue
+		}
+		if contact, isNear := head.OverlapsCapsule(capsule); isNear {
+			near = true
+			overlaps = overlaps || (contact.Penetration > nearMissGap) // The head without the gap overlaps
+		}
+	}
+	return
+}
+
+// queryCandidates finds the units in the bounds with the broadphase. The bounds are split at the world
+// edges, and the candidates are stored in the scratch buffer.
+func (g *gameScene) queryCandidates(bounds *c.RectF32) {
+	g.queryBounds.Update(bounds)
+	g.candidates = g.broadphase.CandidatesInRects(g.queryBounds.Rects[:g.queryBounds.NumRects], g.candidates[:0])
+}
+
+// registerCollidables adds the units of the snake to the broadphase for the collision checks of this tick.
+func (g *gameScene) registerCollidables() {
+	g.broadphase.Clear()
+	for unit := g.snake.UnitHead; unit != nil; unit = unit.Next {
+		g.broadphase.Insert(unit)
+	}
 }
 
 func (g *gameScene) checkIntersection() {
-	curUnit := g.snake.UnitHead.Next
-	if curUnit == nil {
+	if contact, hit := g.snake.Hit(); hit {
+		g.gameOver = true
+		g.collisionPoint = contact.Point.To32()
+		g.particles.EmitShatter(g.snake)
+		playSoundHit()
+		g.publishEvent(telemetry.Event{
+			Kind:      telemetry.KindCollision,
+			Pos:       &telemetry.Vec{X: contact.Point.X, Y: contact.Point.Y},
+			UnitIndex: g.hitUnitIndex(),
+		})
+		g.finishGame()
+	}
+}
+
+// hitUnitIndex returns the index of the unit the head has hit, counted from the head.
+func (g *gameScene) hitUnitIndex() int {
+	var index int
+	for unit := g.snake.UnitHead; (unit != nil) && (unit != g.hitUnit); unit = unit.Next {
+		index++
+	}
+	return index
+}
+
+// setObstacles sets the obstacles of the snake. It passes through them in god mode and in the modes
+// without self-collision.
+func (g *gameScene) setObstacles() {
+	if g.godMode || g.mode.passThrough {
+		g.snake.Obstacles = nil
 		return
 	}
+	g.snake.Obstacles = g
+}
 
-	var tolerance float32 = param.ToleranceDefault
-	if len(curUnit.CompCollision.Rects) > 1 { // If second unit is on an edge
-		tolerance = param.ToleranceScreenEdge // To avoid false collisions on screen edges
+// Swept implements the snake's Obstacles interface. The units registered in the last tick are tested
+// against the capsule swept by the head.
+func (g *gameScene) Swept(sweep c.Capsule) bool {
+	end := c.Vec64{X: sweep.Start.X + sweep.Direction.X*sweep.Length, Y: sweep.Start.Y + sweep.Direction.Y*sweep.Length}
+	bounds := c.RectF32{
+		Pos: c.Vec32{
+			X: float32(math.Min(sweep.Start.X, end.X) - sweep.Radius),
+			Y: float32(math.Min(sweep.Start.Y, end.Y) - sweep.Radius),
+		},
+		Size: c.Vec32{
+			X: float32(math.Abs(end.X-sweep.Start.X) + 2.0*sweep.Radius),
+			Y: float32(math.Abs(end.Y-sweep.Start.Y) + 2.0*sweep.Radius),
+		},
+	}
+	g.queryCandidates(&bounds)
+	for _, candidate := range g.candidates {
+		capsule, ok := g.snake.BodyCapsule(candidate.(*s.Unit))
+		if ok && (sweep.SegmentDistance(capsule) < sweep.Radius+capsule.Radius-param.ToleranceDefault) {
+			return true
+		}
 	}
+	return false
+}
 
-	for curUnit != nil {
-		if object.Collides(g.snake.UnitHead, curUnit, tolerance) {
-			g.gameOver = true
-			playSoundHit()
-			return
+// Hit implements the snake's Obstacles interface. It is called after Swept has found a hit. The units near
+// the head are tested in the order from the neck to the tail.
+func (g *gameScene) Hit() (c.Contact, bool) {
+	// The sub-steps are inside of the swept bounds, so the candidates of the sweep are used.
+	for _, candidate := range g.candidates {
+		if contact, hits := g.snake.HitsItself(candidate.(*s.Unit)); hits {
+			g.hitUnit = candidate.(*s.Unit)
+			return contact, true
 		}
-		curUnit = curUnit.Next
 	}
+	return c.Contact{}, false
 }
 
 func (g *gameScene) calcFoodDist() float32 {
@@ -119,29 +391,55 @@ func (g *gameScene) calcFoodDist() float32 {
 
 	headLoc := g.snake.UnitHead.HeadCenter
 	foodLoc := g.food.Center.To64()
+	worldWidth := float64(param.WorldWidth)
+	worldHeight := float64(param.WorldHeight)
 
 	// In screen distance
 	minDist := c.Distance(headLoc, foodLoc)
 
-	if headLoc.X < param.HalfScreenWidth { // Left projection distance
-		virtualFood := c.Vec64{X: foodLoc.X - param.ScreenWidth, Y: foodLoc.Y}
+	if headLoc.X < worldWidth/2.0 { // Left projection distance
+		virtualFood := c.Vec64{X: foodLoc.X - worldWidth, Y: foodLoc.Y}
 		minDist = math.Min(minDist, c.Distance(headLoc, virtualFood))
-	} else if headLoc.X >= param.HalfScreenWidth { // Right projection distance
-		virtualFood := c.Vec64{X: foodLoc.X + param.ScreenWidth, Y: foodLoc.Y}
+	} else if headLoc.X >= worldWidth/2.0 { // Right projection distance
+		virtualFood := c.Vec64{X: foodLoc.X + worldWidth, Y: foodLoc.Y}
 		minDist = math.Min(minDist, c.Distance(headLoc, virtualFood))
 	}
 
-	if headLoc.Y < param.HalfScreenHeight { // Upper projection distance
-		virtualFood := c.Vec64{X: foodLoc.X, Y: foodLoc.Y - param.ScreenHeight}
+	if headLoc.Y < worldHeight/2.0 { // Upper projection distance
+		virtualFood := c.Vec64{X: foodLoc.X, Y: foodLoc.Y - worldHeight}
 		minDist = math.Min(minDist, c.Distance(headLoc, virtualFood))
-	} else if headLoc.Y >= param.HalfScreenHeight { // Bottom projection distance
-		virtualFood := c.Vec64{X: foodLoc.X, Y: foodLoc.Y + param.ScreenHeight}
+	} else if headLoc.Y >= worldHeight/2.0 { // Bottom projection distance
+		virtualFood := c.Vec64{X: foodLoc.X, Y: foodLoc.Y + worldHeight}
 		minDist = math.Min(minDist, c.Distance(headLoc, virtualFood))
 	}
 
 	return float32(minDist)
 }
 
+// updateToasts shows the achievements unlocked in this tick and animates the toasts. They are animated in
+// real time, even if the game is paused.
+func (g *gameScene) updateToasts() {
+	for _, iAchievement := range tracker.popUnlocked() {
+		g.toasts = append(g.toasts, object.NewToast(textAchievement, achievements[iAchievement].name, fontFaceDebug, fontFaceMenu))
+	}
+
+	alive := g.toasts[:0]
+	for _, toast := range g.toasts {
+		if !toast.Update() {
+			alive = append(alive, toast)
+		}
+	}
+	g.toasts = alive
+}
+
+// drawToasts stacks the toasts at the top of the screen.
+func (g *gameScene) drawToasts(screen *ebiten.Image) {
+	posY := float32(toastShiftY)
+	for _, toast := range g.toasts {
+		posY += toast.Draw(screen, posY)
+	}
+}
+
 func (g *gameScene) updateScoreAnims() {
 	for index, scoreAnim := range g.scoreAnimList {
 		if scoreAnim.Update() {
@@ -152,16 +450,33 @@ func (g *gameScene) updateScoreAnims() {
 }
 
 func (g *gameScene) handleInput() {
-	pressedLeft := inpututil.IsKeyJustPressed(ebiten.KeyLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA)
-	pressedRight := inpututil.IsKeyJustPressed(ebiten.KeyRight) || inpututil.IsKeyJustPressed(ebiten.KeyD)
-	pressedUp := inpututil.IsKeyJustPressed(ebiten.KeyUp) || inpututil.IsKeyJustPressed(ebiten.KeyW)
-	pressedDown := inpututil.IsKeyJustPressed(ebiten.KeyDown) || inpututil.IsKeyJustPressed(ebiten.KeyS)
+	pressedLeft := input.IsActionJustPressed(input.ActionTurnLeft)
+	pressedRight := input.IsActionJustPressed(input.ActionTurnRight)
+	pressedUp := input.IsActionJustPressed(input.ActionTurnUp)
+	pressedDown := input.IsActionJustPressed(input.ActionTurnDown)
 
 	if !pressedLeft && !pressedRight && !pressedUp && !pressedDown {
 		return
 	}
 
-	// Determine the new direction.
+	pressedDirs := [...]bool{
+		s.DirectionUp:    pressedUp,
+		s.DirectionDown:  pressedDown,
+		s.DirectionLeft:  pressedLeft,
+		s.DirectionRight: pressedRight,
+	}
+
+	if turnPolicy.BufferInputs {
+		// Take every pressed direction one after another.
+		for dir, pressed := range pressedDirs {
+			if pressed {
+				g.turnSnake(s.DirectionT(dir))
+			}
+		}
+		return
+	}
+
+	// Determine the new direction. The U-turn macro is triggered only by the key opposite to the direction.
 	dirCurrent := g.snake.LastDirection()
 	dirNew := dirCurrent
 	if dirCurrent.IsVertical() {
@@ -169,27 +484,56 @@ func (g *gameScene) handleInput() {
 			dirNew = s.DirectionLeft
 		} else if pressedRight {
 			dirNew = s.DirectionRight
+		} else if pressedDirs[dirCurrent.Opposite()] && turnPolicy.UTurnMacro {
+			dirNew = dirCurrent.Opposite()
 		}
 	} else {
 		if pressedUp {
 			dirNew = s.DirectionUp
 		} else if pressedDown {
 			dirNew = s.DirectionDown
+		} else if pressedDirs[dirCurrent.Opposite()] && turnPolicy.UTurnMacro {
+			dirNew = dirCurrent.Opposite()
 		}
 	}
 
+	g.turnSnake(dirNew)
+}
+
+// turnSnake turns the snake to the new direction if it is a valid turn.
+func (g *gameScene) turnSnake(dirNew s.DirectionT) {
+	dirCurrent := g.snake.LastDirection()
 	if dirNew == dirCurrent {
 		return
 	}
 
-	// Create a new turn and take it
-	newTurn := s.NewTurn(dirCurrent, dirNew)
-	g.snake.TurnTo(newTurn, false)
+	numQueued := len(g.snake.TurnQueue())
+	if dirNew == dirCurrent.Opposite() {
+		if !turnPolicy.UTurnMacro {
+			return
+		}
+		g.snake.TurnBack()
+	} else {
+		// Create a new turn and take it
+		newTurn := s.NewTurn(dirCurrent, dirNew)
+		g.snake.TurnTo(newTurn, false)
+	}
 
+	// Log whether the turn is taken, queued or ignored.
+	kind := telemetry.KindTurnIgnored
+	if len(g.snake.TurnQueue()) > numQueued {
+		kind = telemetry.KindTurnQueued
+	} else if g.snake.UnitHead.Direction != dirCurrent {
+		kind = telemetry.KindTurn
+	}
+	if kind != telemetry.KindTurnIgnored {
+		g.replay.addTurn(g.ticks+1, dirNew) // The turn is taken before the next step
+	}
+	g.publishEvent(telemetry.Event{Kind: kind, Direction: dirNew.String()})
 }
 
 func (g *gameScene) handleSettingsInputs() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyG) {
+	if input.IsActionJustPressed(input.ActionToggleDebug) {
 		param.DebugUnits = !param.DebugUnits
 		var numUnit uint8
 		for unit := g.snake.UnitHead; unit != nil; unit = unit.Next {
@@ -202,65 +546,103 @@ func (g *gameScene) handleSettingsInputs() {
 		}
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+	if input.IsActionJustPressed(input.ActionPause) {
 		g.paused = !g.paused
-		if g.paused && (musicState == musicOn) {
-			playerMusic.Pause()
-			musicState = musicPaused
-		} else if musicState == musicPaused {
-			playerMusic.Play()
-			musicState = musicOn
+		if !g.adaptiveMusic { // Adaptive music is muffled instead of being paused
+			sound.SetMusicPaused(g.paused)
+		}
+		if g.paused {
+			g.publishEvent(telemetry.Event{Kind: telemetry.KindPause})
+			flushTelemetry()
+		} else {
+			g.publishEvent(telemetry.Event{Kind: telemetry.KindResume})
 		}
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
-		if musicState == musicOn {
-			musicState = musicMuted
-			playerMusic.Pause()
-		} else if (musicState == musicMuted) && !g.paused {
-			musicState = musicOn
-			playerMusic.Play()
-		}
+	if input.IsActionJustPressed(input.ActionToggleMusic) {
+		sound.ToggleMusic()
+		g.publishMute("music", sound.MusicMuted())
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyF) {
-		param.PrintFPS = !param.PrintFPS
+	if input.IsActionJustPressed(input.ActionNextTrack) {
+		sound.NextTrack()
+	}
+
+	if input.IsActionJustPressed(input.ActionNextTheme) {
+		selectTheme(+1)
+	}
+
+	if input.IsActionJustPressed(input.ActionToggleTurnDebug) {
+		param.DebugTurns = !param.DebugTurns
+	}
+
+	if input.IsActionJustPressed(input.ActionSlowMotion) {
+		toggleTimeScale(timeScaleSlow)
+	}
+
+	if input.IsActionJustPressed(input.ActionFastForward) {
+		toggleTimeScale(timeScaleFast)
 	}
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyN) {
-		playSounds = !playSounds
+	if input.IsActionJustPressed(input.ActionStepTick) && g.paused {
+		g.stepRequested = true
 	}
 
-	// if inpututil.IsKeyJustPressed(ebiten.KeyN) {
-	// 	g.snake.Grow()
-	// 	g.snake.Grow()
-	// }
+	if input.IsActionJustPressed(input.ActionToggleFPS) {
+		param.PrintFPS = !param.PrintFPS
+	}
+
+	if input.IsActionJustPressed(input.ActionToggleSounds) {
+		sound.ToggleSFX()
+		g.publishMute("sfx", sound.SFXMuted())
+	}
 }
 
 func (g *gameScene) checkFood(distToFood float32) {
 	if !g.food.IsActive {
 		// If food has spawned on the snake, respawn it elsewhere.
-		for unit := g.snake.UnitHead; unit != nil; unit = unit.Next {
-			if object.Collides(unit, g.food, param.ToleranceDefault) {
+		g.candidates = g.broadphase.Candidates(g.food, g.candidates[:0])
+		for _, candidate := range g.candidates {
+			if _, overlaps := g.food.Circle().OverlapsCapsule(candidate.(*s.Unit).Capsule()); overlaps {
+				g.publishFoodEvent(telemetry.KindFoodRespawn)
 				g.food = object.NewFoodRandLoc()
 				return
 			}
 		}
 		// Food has spawned in an open position, activate it.
 		g.food.IsActive = true
+		g.replay.addFood(g.ticks, g.food.Center)
+		g.publishFoodEvent(telemetry.KindFoodSpawn)
 		return
 	}
 
 	// Check for collision with food
-	if distToFood <= param.RadiusEating {
+	// The food is eaten when it touches the mouth.
+	if _, eats := g.snake.HeadCircle(param.RadiusMouth).Overlaps(g.food.Circle()); eats {
 		g.snake.Grow()
-		g.triggerScoreAnim()
+		points := g.scoring.eatFood()
+		g.publishFoodEvent(telemetry.KindFoodEat)
+		g.triggerScoreAnim(points)
+		g.particles.EmitBurst(g.food.Center, &param.ColorFood)
 		g.food = object.NewFoodRandLoc()
-		playSoundEating()
+		playSoundEating(int(g.snake.FoodEaten))
+	}
+}
+
+// publishFoodEvent publishes the event at the position of the current food.
+func (g *gameScene) publishFoodEvent(kind telemetry.Kind) {
+	event := telemetry.Event{
+		Kind: kind,
+		Pos:  &telemetry.Vec{X: float64(g.food.Center.X), Y: float64(g.food.Center.Y)},
 	}
+	if kind == telemetry.KindFoodEat {
+		event.Score = g.scoring.score
+	}
+	g.publishEvent(event)
 }
 
-func (g *gameScene) triggerScoreAnim() {
+// triggerScoreAnim shows the points earned at the tip of the head.
+func (g *gameScene) triggerScoreAnim(points int) {
 	corrCenter := g.snake.UnitHead.HeadCenter
 
 	// Correct the x and y position so the base score animation position will be the tip of the head,
@@ -276,27 +658,47 @@ func (g *gameScene) triggerScoreAnim() {
 		corrCenter.X -= param.RadiusSnake
 	}
 
-	g.scoreAnimList = append(g.scoreAnimList, object.NewScoreAnim(corrCenter.To32()))
+	g.scoreAnimList = append(g.scoreAnimList, object.NewScoreAnim(corrCenter.To32(), points))
 }
 
 func (g *gameScene) draw(screen *ebiten.Image) {
-	screen.Fill(param.ColorBackground)
+	g.setRenderAlpha()
+	drawBackground(screen)
+	if worldScrolls() {
+		drawGrid(screen)
+	}
 
 	// Draw food
 	g.food.Draw(screen)
 
-	// Draw the snake
-	g.snake.Draw(screen)
+	// Draw the snake, it is shattered into particles when it dies.
+	died := g.gameOver && !g.ended
+	if !died {
+		g.snake.Draw(screen)
+	}
+	g.particles.Draw(screen)
+	if died {
+		g.drawDeath(screen)
+	}
 
 	// Draw score anim
 	for _, scoreAnim := range g.scoreAnimList {
 		scoreAnim.Draw(screen)
 	}
 
+	if worldScrolls() {
+		drawMinimap(screen, []*s.Snake{g.snake}, g.food)
+	}
+
 	// Draw score text
 	g.drawScore(screen)
+	if g.mode.drawHUD != nil {
+		g.mode.drawHUD(g, screen)
+	}
+	g.drawToasts(screen)
 
 	drawFPS(screen)
+	drawTimeScale(screen)
 
 	if param.DebugUnits {
 		// Mark cursor
@@ -310,19 +712,73 @@ func (g *gameScene) draw(screen *ebiten.Image) {
 	}
 
 	g.printDebugMsgs(screen)
+
+	if g.gameOver {
+		g.drawSummary(screen)
+	}
+
+	g.console.draw(screen)
+}
+
+// toggleTimeScale switches between the scale and the normal speed.
+func toggleTimeScale(scale float64) {
+	if param.TimeScale == scale {
+		param.TimeScale = 1
+	} else {
+		param.TimeScale = scale
+	}
 }
 
 func (g *gameScene) drawScore(screen *ebiten.Image) {
-	msg := fmt.Sprintf("Score: %05d", int(g.snake.FoodEaten)*param.FoodScore)
+	msg := fmt.Sprintf("Score: %05d", g.scoring.score)
 	text.Draw(screen, msg, param.FontFaceScore, scoreTextShiftX, -boundTextScore.Min.Y+scoreTextShiftY, param.ColorScore)
+
+	// Draw the combo multiplier with a bar that shows the remaining combo time
+	if g.scoring.multiplier <= 1 {
+		return
+	}
+	comboY := boundTextScore.Size().Y + scoreTextShiftY + comboTextShiftY
+	msgCombo := fmt.Sprintf("x%.1f", g.scoring.multiplier)
+	boundCombo := text.BoundString(fontFaceMenu, msgCombo)
+	text.Draw(screen, msgCombo, fontFaceMenu, scoreTextShiftX, comboY-boundCombo.Min.Y, param.ColorScore)
+	barX := float64(scoreTextShiftX + boundCombo.Max.X + comboBarShiftX)
+	barY := float64(comboY + (boundCombo.Size().Y-comboBarHeight)/2)
+	ebitenutil.DrawRect(screen, barX, barY, comboBarWidth*g.scoring.comboDecay(), comboBarHeight, param.ColorScore)
 }
 
 func (g *gameScene) printDebugMsgs(screen *ebiten.Image) {
-	// var totalLength float64
-	// for unit := g.snake.UnitHead; unit != nil; unit = unit.Next {
-	// 	totalLength += unit.length
-	// }
-	// ebitenutil.DebugPrint(screen, fmt.Sprintf("Food Eaten: %d   Snake length: %.2f   Speed: %.3f", g.snake.foodEaten, totalLength,  g.snake.speed))
-	// ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Turn Queue Length: %d Cap: %d", len(g.snake.turnQueue), cap(g.snake.turnQueue)), 0, 15)
-	// ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Distance after turn: %.2f", g.snake.distAfterTurn), 0, 30)
+	if !param.DebugTurns {
+		return
+	}
+
+	lineY := turnDebugShiftY
+	printLine := func(msg string) {
+		text.Draw(screen, msg, fontFaceDebug, turnDebugShiftX, lineY, param.ColorDebug)
+		lineY += turnDebugLineSpace
+	}
+
+	// Print the policy and the queued turns
+	printLine(fmt.Sprintf("Queue depth: %d/%d  Stale after: %d ms  Pre-turn: %.0f px  U-turn: %t  Buffer: %t",
+		len(g.snake.TurnQueue()), turnPolicy.MaxQueueDepth, turnPolicy.StaleAfterMs,
+		turnPolicy.PreTurnWindow, turnPolicy.UTurnMacro, turnPolicy.BufferInputs))
+	for iTurn, turn := range g.snake.TurnQueue() {
+		side := "right"
+		if turn.IsTurningLeft() {
+			side = "left"
+		}
+		printLine(fmt.Sprintf("%d: %-5s (%s) waiting %.0f ms", iTurn, turn.DirectionTo(), side, g.snake.TurnAge(turn)*1000))
+	}
+
+	// Draw the distance after the last turn as a bar that fills up when the next same side turn is safe.
+	distAfterTurn := g.snake.DistAfterTurn()
+	printLine(fmt.Sprintf("Distance after turn: %.2f", distAfterTurn))
+	fill := math.Min(distAfterTurn/param.SnakeWidth, 1.0)
+	barY := float64(lineY - turnDebugLineSpace/2)
+	ebitenutil.DrawRect(screen, turnDebugShiftX, barY, turnDebugBarWidth, turnDebugBarHeight, param.ColorSnake1)
+	ebitenutil.DrawRect(screen, turnDebugShiftX, barY, turnDebugBarWidth*fill, turnDebugBarHeight, param.ColorSnake2)
+
+	// Mark where the pre-turn window starts
+	windowStart := math.Max(param.SnakeWidth-param.ToleranceDefault-turnPolicy.PreTurnWindow, 0) / param.SnakeWidth
+	windowX := turnDebugShiftX + turnDebugBarWidth*windowStart
+	ebitenutil.DrawLine(screen, windowX, barY-turnDebugBarHeight/2, windowX, barY+turnDebugBarHeight*1.5, param.ColorFood)
 }
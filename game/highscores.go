@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// High score parameters
+const (
+	settingsHighScores = "highScores"
+	highScoresMax      = 10
+)
+
+// High scores scene layout parameters
+const (
+	textHighScoresTitle = "High Scores"
+	textHighScoresHelp  = "%s/%s: Change mode   %s: Back"
+	textNoHighScores    = "No games played yet"
+	highScoresModeY     = 110
+	highScoresScoreX    = 160
+	highScoresTimeX     = 360
+	highScoresDateX     = 560
+)
+
+// highScore is an entry of the high-score table of a mode.
+type highScore struct {
+	Score     int       `json:"score"`
+	TimeAlive float64   `json:"timeAlive"` // seconds
+	Date      time.Time `json:"date"`
+}
+
+// highScores are the tables of the modes by their IDs, sorted from the highest score.
+var highScores = make(map[string][]highScore)
+
+func init() {
+	if err := settings.Load(settingsHighScores, &highScores); err != nil {
+		fmt.Println("Could not load the high scores:", err)
+	}
+
+	// The best score was saved with the records before the modes, it belongs to the classic mode.
+	classic := gameModes[modeClassic].id
+	if _, ok := highScores[classic]; ok {
+		return
+	}
+	var legacy struct {
+		BestScore int `json:"bestScore"`
+	}
+	if err := settings.Load(settingsRecords, &legacy); (err == nil) && (legacy.BestScore > 0) {
+		addHighScore(&gameModes[modeClassic], highScore{Score: legacy.BestScore})
+	}
+}
+
+// bestScore returns the highest score of the mode, or zero if it has not been played.
+func bestScore(mode *gameMode) int {
+	if table := highScores[mode.id]; len(table) > 0 {
+		return table[0].Score
+	}
+	return 0
+}
+
+// addHighScore inserts the entry into the table of the mode and saves the tables. It returns the rank of
+// the entry starting from 1, or 0 if it is not high enough for the table.
+func addHighScore(mode *gameMode, entry highScore) int {
+	if entry.Score <= 0 {
+		return 0
+	}
+
+	table := highScores[mode.id]
+	// Equal scores are ranked by their date, the earlier one stays ahead.
+	rank := sort.Search(len(table), func(i int) bool {
+		return table[i].Score < entry.Score
+	})
+	if rank >= highScoresMax {
+		return 0
+	}
+
+	table = append(table, highScore{})
+	copy(table[rank+1:], table[rank:])
+	table[rank] = entry
+	if len(table) > highScoresMax {
+		table = table[:highScoresMax]
+	}
+	highScores[mode.id] = table
+
+	if err := settings.Save(settingsHighScores, &highScores); err != nil {
+		fmt.Println("Could not save the high scores:", err)
+	}
+	return rank + 1
+}
+
+// pagesHelp fills the keys of the previous page, next page and back actions in the help text.
+func pagesHelp(format string) string {
+	return fmt.Sprintf(format, input.KeyLabel(input.ActionMenuPrev), input.KeyLabel(input.ActionMenuNext),
+		input.KeyLabel(input.ActionMenuBack))
+}
+
+// highScoresScene shows the high-score table of a mode at a time.
+type highScoresScene struct {
+	mode modeID
+}
+
+func newHighScoresScene() *highScoresScene {
+	return &highScoresScene{}
+}
+
+func (h *highScoresScene) update() bool {
+	switch {
+	case input.IsActionJustPressed(input.ActionMenuPrev):
+		h.mode = (h.mode + modeTotal - 1) % modeTotal
+	case input.IsActionJustPressed(input.ActionMenuNext):
+		h.mode = (h.mode + 1) % modeTotal
+	case input.IsActionJustPressed(input.ActionMenuBack):
+		return true
+	}
+
+	return false
+}
+
+func (h *highScoresScene) draw(screen *ebiten.Image) {
+	drawBackground(screen)
+
+	// Draw title
+	boundTitle := text.BoundString(param.FontFaceScore, textHighScoresTitle)
+	text.Draw(screen, textHighScoresTitle, param.FontFaceScore,
+		(param.ScreenWidth-boundTitle.Size().X)/2-boundTitle.Min.X, controlsTitleShiftY-boundTitle.Min.Y, param.ColorScore)
+
+	// Draw mode name
+	mode := &gameModes[h.mode]
+	name := "< " + mode.name + " >"
+	boundName := text.BoundString(fontFaceMenu, name)
+	text.Draw(screen, name, fontFaceMenu, (param.ScreenWidth-boundName.Size().X)/2-boundName.Min.X, highScoresModeY, param.ColorDebug)
+
+	// Draw table
+	table := highScores[mode.id]
+	if len(table) == 0 {
+		text.Draw(screen, textNoHighScores, fontFaceMenu, controlsLabelX, controlsTableShiftY+controlsRowHeight, param.ColorDebug)
+	}
+	for iEntry, entry := range table {
+		rowY := controlsTableShiftY + (iEntry+1)*controlsRowHeight
+		clr := param.ColorDebug
+		if iEntry == 0 {
+			clr = param.ColorScore
+		}
+		text.Draw(screen, fmt.Sprintf("%2d.", iEntry+1), fontFaceMenu, controlsLabelX, rowY, clr)
+		text.Draw(screen, fmt.Sprintf("%05d", entry.Score), fontFaceMenu, highScoresScoreX, rowY, clr)
+		if !entry.Date.IsZero() { // The entries moved from the records have only the score
+			text.Draw(screen, formatDuration(entry.TimeAlive), fontFaceMenu, highScoresTimeX, rowY, clr)
+			text.Draw(screen, entry.Date.Format("2006-01-02 15:04"), fontFaceMenu, highScoresDateX, rowY, clr)
+		}
+	}
+
+	text.Draw(screen, pagesHelp(textHighScoresHelp), fontFaceDebug, controlsLabelX, param.ScreenHeight-controlsHelpShiftY, param.ColorDebug)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package input
+
+// Action is a game command that can be triggered by the bound keys and gamepad buttons.
+type Action uint8
+
+const (
+	ActionTurnUp Action = iota
+	ActionTurnDown
+	ActionTurnLeft
+	ActionTurnRight
+	ActionPause
+	ActionToggleMusic
+	ActionToggleSounds
+	ActionToggleFPS
+	ActionToggleDebug
+	ActionToggleTurnDebug
+	ActionNextTrack
+	ActionNextTheme
+	ActionToggleFullscreen
+	ActionSlowMotion
+	ActionFastForward
+	ActionStepTick
+	ActionMenuBack
+	ActionMenuPrev
+	ActionMenuNext
+	ActionOpenControls
+	ActionOpenOptions
+	ActionOpenSkins
+	ActionOpenBenchmark
+	ActionOpenAchievements
+	ActionOpenHighScores
+	ActionPlayClassic
+	ActionPlayTimeAttack
+	ActionPlaySurvival
+	ActionPlayZen
+	ActionRestart
+	ActionSaveReplay
+	ActionTotal
+)
+
+// Context is the part of the game where an action is handled. The bindings of the actions conflict only
+// if they can be handled at the same time.
+type Context uint8
+
+const (
+	ContextGlobal  Context = iota // Everywhere
+	ContextPlay                   // In the game, the settings actions work on the game over summary as well
+	ContextSummary                // On the game over summary
+	ContextMenu                   // On the title screen and in the menus
+)
+
+var actionNames = [ActionTotal]string{
+	ActionTurnUp:       "TurnUp",
+	ActionTurnDown:     "TurnDown",
+	ActionTurnLeft:     "TurnLeft",
+	ActionTurnRight:    "TurnRight",
+	ActionPause:        "Pause",
+	ActionToggleMusic:  "ToggleMusic",
+	ActionToggleSounds: "ToggleSounds",
+	ActionToggleFPS:    "ToggleFPS",
+	ActionToggleDebug:  "ToggleDebug",
+
+	ActionToggleTurnDebug: "ToggleTurnDebug",
+	ActionNextTrack:       "NextTrack",
+	ActionNextTheme:       "NextTheme",
+
+	ActionToggleFullscreen: "ToggleFullscreen",
+	ActionSlowMotion:       "SlowMotion",
+	ActionFastForward:      "FastForward",
+	ActionStepTick:         "StepTick",
+
+	ActionMenuBack:         "MenuBack",
+	ActionMenuPrev:         "MenuPrev",
+	ActionMenuNext:         "MenuNext",
+	ActionOpenControls:     "OpenControls",
+	ActionOpenOptions:      "OpenOptions",
+	ActionOpenSkins:        "OpenSkins",
+	ActionOpenBenchmark:    "OpenBenchmark",
+	ActionOpenAchievements: "OpenAchievements",
+	ActionOpenHighScores:   "OpenHighScores",
+	ActionPlayClassic:      "PlayClassic",
+	ActionPlayTimeAttack:   "PlayTimeAttack",
+	ActionPlaySurvival:     "PlaySurvival",
+	ActionPlayZen:          "PlayZen",
+	ActionRestart:          "Restart",
+	ActionSaveReplay:       "SaveReplay",
+}
+
+var actionLabels = [ActionTotal]string{
+	ActionTurnUp:       "Turn up",
+	ActionTurnDown:     "Turn down",
+	ActionTurnLeft:     "Turn left",
+	ActionTurnRight:    "Turn right",
+	ActionPause:        "Pause/Continue",
+	ActionToggleMusic:  "Pause/Play music",
+	ActionToggleSounds: "Sound effects",
+	ActionToggleFPS:    "Show TPS/FPS",
+	ActionToggleDebug:  "Debug units",
+
+	ActionToggleTurnDebug: "Debug turn queue",
+	ActionNextTrack:       "Next music track",
+	ActionNextTheme:       "Next theme",
+
+	ActionToggleFullscreen: "Fullscreen",
+	ActionSlowMotion:       "Slow motion",
+	ActionFastForward:      "Fast forward",
+	ActionStepTick:         "Step a tick (paused)",
+
+	ActionMenuBack:         "Back/End a Zen game",
+	ActionMenuPrev:         "Previous page",
+	ActionMenuNext:         "Next page",
+	ActionOpenControls:     "Controls",
+	ActionOpenOptions:      "Options",
+	ActionOpenSkins:        "Skins",
+	ActionOpenBenchmark:    "Benchmark",
+	ActionOpenAchievements: "Achievements",
+	ActionOpenHighScores:   "High scores",
+	ActionPlayClassic:      "Play Classic",
+	ActionPlayTimeAttack:   "Play Time Attack",
+	ActionPlaySurvival:     "Play Survival",
+	ActionPlayZen:          "Play Zen",
+	ActionRestart:          "Restart",
+	ActionSaveReplay:       "Save replay",
+}
+
+var actionContexts = [ActionTotal]Context{
+	ActionTurnUp:           ContextPlay,
+	ActionTurnDown:         ContextPlay,
+	ActionTurnLeft:         ContextPlay,
+	ActionTurnRight:        ContextPlay,
+	ActionPause:            ContextPlay,
+	ActionToggleMusic:      ContextPlay,
+	ActionToggleSounds:     ContextPlay,
+	ActionToggleFPS:        ContextPlay,
+	ActionToggleDebug:      ContextPlay,
+	ActionToggleTurnDebug:  ContextPlay,
+	ActionNextTrack:        ContextPlay,
+	ActionNextTheme:        ContextPlay,
+	ActionToggleFullscreen: ContextGlobal,
+	ActionSlowMotion:       ContextPlay,
+	ActionFastForward:      ContextPlay,
+	ActionStepTick:         ContextPlay,
+	ActionMenuBack:         ContextGlobal,
+	ActionMenuPrev:         ContextMenu,
+	ActionMenuNext:         ContextMenu,
+	ActionOpenControls:     ContextMenu,
+	ActionOpenOptions:      ContextMenu,
+	ActionOpenSkins:        ContextMenu,
+	ActionOpenBenchmark:    ContextMenu,
+	ActionOpenAchievements: ContextMenu,
+	ActionOpenHighScores:   ContextMenu,
+	ActionPlayClassic:      ContextMenu,
+	ActionPlayTimeAttack:   ContextMenu,
+	ActionPlaySurvival:     ContextMenu,
+	ActionPlayZen:          ContextMenu,
+	ActionRestart:          ContextSummary,
+	ActionSaveReplay:       ContextSummary,
+}
+
+// String returns the name of the action used in the settings file.
+func (a Action) String() string {
+	if a >= ActionTotal {
+		return "Unknown"
+	}
+	return actionNames[a]
+}
+
+// Label returns a human readable description of the action.
+func (a Action) Label() string {
+	if a >= ActionTotal {
+		return "Unknown"
+	}
+	return actionLabels[a]
+}
+
+// Context returns the part of the game where the action is handled.
+func (a Action) Context() Context {
+	if a >= ActionTotal {
+		return ContextGlobal
+	}
+	return actionContexts[a]
+}
+
+// handledWith returns true if the actions can be handled at the same time.
+func (a Action) handledWith(other Action) bool {
+	contextA, contextB := a.Context(), other.Context()
+	if (contextA == ContextGlobal) || (contextB == ContextGlobal) || (contextA == contextB) {
+		return true
+	}
+	return ((contextA == ContextPlay) && (contextB == ContextSummary)) ||
+		((contextA == ContextSummary) && (contextB == ContextPlay))
+}
@@ -0,0 +1,261 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package input
+
+import (
+	"fmt"
+
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	settingsSection = "controls"
+	NumKeySlots     = 2 // Number of keyboard keys that can be bound to an action
+)
+
+// Binding holds the keyboard keys and the standard layout gamepad buttons of an action.
+type Binding struct {
+	Keys    []ebiten.Key                   `json:"keys"`
+	Buttons []ebiten.StandardGamepadButton `json:"buttons"`
+}
+
+var defaultBindings = [ActionTotal]Binding{
+	ActionTurnUp: {
+		Keys:    []ebiten.Key{ebiten.KeyW, ebiten.KeyUp},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftTop},
+	},
+	ActionTurnDown: {
+		Keys:    []ebiten.Key{ebiten.KeyS, ebiten.KeyDown},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftBottom},
+	},
+	ActionTurnLeft: {
+		Keys:    []ebiten.Key{ebiten.KeyA, ebiten.KeyLeft},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftLeft},
+	},
+	ActionTurnRight: {
+		Keys:    []ebiten.Key{ebiten.KeyD, ebiten.KeyRight},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftRight},
+	},
+	ActionPause: {
+		Keys:    []ebiten.Key{ebiten.KeyP},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonCenterRight},
+	},
+	ActionToggleMusic: {
+		Keys:    []ebiten.Key{ebiten.KeyM},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightTop},
+	},
+	ActionToggleSounds: {
+		Keys:    []ebiten.Key{ebiten.KeyN},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightLeft},
+	},
+	ActionToggleFPS: {
+		Keys: []ebiten.Key{ebiten.KeyF},
+	},
+	ActionToggleDebug: {
+		Keys: []ebiten.Key{ebiten.KeyG},
+	},
+	ActionToggleTurnDebug: {
+		Keys: []ebiten.Key{ebiten.KeyH},
+	},
+	ActionNextTrack: {
+		Keys:    []ebiten.Key{ebiten.KeyT},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonFrontTopRight},
+	},
+	ActionNextTheme: {
+		Keys: []ebiten.Key{ebiten.KeyC},
+	},
+	ActionToggleFullscreen: {
+		Keys: []ebiten.Key{ebiten.KeyF11},
+	},
+	ActionSlowMotion: {
+		Keys: []ebiten.Key{ebiten.KeyBracketLeft},
+	},
+	ActionFastForward: {
+		Keys: []ebiten.Key{ebiten.KeyBracketRight},
+	},
+	ActionStepTick: {
+		Keys: []ebiten.Key{ebiten.KeyPeriod},
+	},
+	ActionMenuBack: {
+		Keys:    []ebiten.Key{ebiten.KeyEscape},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightRight},
+	},
+	ActionMenuPrev: {
+		Keys:    []ebiten.Key{ebiten.KeyLeft},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftLeft},
+	},
+	ActionMenuNext: {
+		Keys:    []ebiten.Key{ebiten.KeyRight},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonLeftRight},
+	},
+	ActionOpenControls: {
+		Keys: []ebiten.Key{ebiten.KeyTab},
+	},
+	ActionOpenOptions: {
+		Keys: []ebiten.Key{ebiten.KeyO},
+	},
+	ActionOpenSkins: {
+		Keys: []ebiten.Key{ebiten.KeyK},
+	},
+	ActionOpenBenchmark: {
+		Keys: []ebiten.Key{ebiten.KeyB},
+	},
+	ActionOpenAchievements: {
+		Keys: []ebiten.Key{ebiten.KeyV},
+	},
+	ActionOpenHighScores: {
+		Keys: []ebiten.Key{ebiten.KeyH},
+	},
+	ActionPlayClassic: {
+		Keys: []ebiten.Key{ebiten.Key1},
+	},
+	ActionPlayTimeAttack: {
+		Keys: []ebiten.Key{ebiten.Key2},
+	},
+	ActionPlaySurvival: {
+		Keys: []ebiten.Key{ebiten.Key3},
+	},
+	ActionPlayZen: {
+		Keys: []ebiten.Key{ebiten.Key4},
+	},
+	ActionRestart: {
+		Keys:    []ebiten.Key{ebiten.KeyEnter, ebiten.KeyR},
+		Buttons: []ebiten.StandardGamepadButton{ebiten.StandardGamepadButtonRightBottom},
+	},
+	ActionSaveReplay: {
+		Keys: []ebiten.Key{ebiten.KeyF5}, // Not S, which turns down while playing
+	},
+}
+
+var bindings [ActionTotal]Binding
+
+func init() {
+	ResetBindings()
+
+	if err := LoadBindings(); err != nil {
+		fmt.Println("input: could not load the key bindings:", err)
+	}
+}
+
+// ResetBindings restores the default bindings of all actions.
+func ResetBindings() {
+	for action := range defaultBindings {
+		bindings[action] = defaultBindings[action].clone()
+	}
+}
+
+// LoadBindings overrides the current bindings with the ones in the settings file.
+func LoadBindings() error {
+	saved := make(map[string]Binding)
+	if err := settings.Load(settingsSection, &saved); err != nil {
+		return err
+	}
+
+	for action := ActionTurnUp; action < ActionTotal; action++ {
+		if binding, ok := saved[action.String()]; ok {
+			bindings[action] = binding
+		}
+	}
+	return nil
+}
+
+// SaveBindings writes the current bindings to the settings file.
+func SaveBindings() error {
+	saved := make(map[string]Binding, ActionTotal)
+	for action := ActionTurnUp; action < ActionTotal; action++ {
+		saved[action.String()] = bindings[action]
+	}
+	return settings.Save(settingsSection, saved)
+}
+
+// BindingOf returns a copy of the binding of the given action.
+func BindingOf(action Action) Binding {
+	return bindings[action].clone()
+}
+
+// BindKey binds the key to the given key slot of the action.
+func BindKey(action Action, slot int, key ebiten.Key) {
+	binding := &bindings[action]
+	if slot < len(binding.Keys) {
+		binding.Keys[slot] = key
+		return
+	}
+	binding.Keys = append(binding.Keys, key)
+}
+
+// UnbindKey removes the key in the given key slot of the action.
+func UnbindKey(action Action, slot int) {
+	binding := &bindings[action]
+	if slot < len(binding.Keys) {
+		binding.Keys = append(binding.Keys[:slot], binding.Keys[slot+1:]...)
+	}
+}
+
+// BindButton binds the gamepad button to the action, replacing the previous one.
+func BindButton(action Action, button ebiten.StandardGamepadButton) {
+	bindings[action].Buttons = []ebiten.StandardGamepadButton{button}
+}
+
+// UnbindButtons removes the gamepad buttons of the action.
+func UnbindButtons(action Action) {
+	bindings[action].Buttons = nil
+}
+
+// Conflicts returns true for every action which shares a key or a button with another action that can be
+// handled at the same time.
+func Conflicts() (conflicts [ActionTotal]bool) {
+	for actionA := ActionTurnUp; actionA < ActionTotal; actionA++ {
+		for actionB := actionA + 1; actionB < ActionTotal; actionB++ {
+			if actionA.handledWith(actionB) && bindings[actionA].overlaps(&bindings[actionB]) {
+				conflicts[actionA] = true
+				conflicts[actionB] = true
+			}
+		}
+	}
+	return
+}
+
+func (b Binding) clone() Binding {
+	return Binding{
+		Keys:    append([]ebiten.Key(nil), b.Keys...),
+		Buttons: append([]ebiten.StandardGamepadButton(nil), b.Buttons...),
+	}
+}
+
+func (b *Binding) overlaps(other *Binding) bool {
+	for _, keyA := range b.Keys {
+		for _, keyB := range other.Keys {
+			if keyA == keyB {
+				return true
+			}
+		}
+	}
+
+	for _, buttonA := range b.Buttons {
+		for _, buttonB := range other.Buttons {
+			if buttonA == buttonB {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,190 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package input
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Ebitengine updates the input once per frame, and a frame can take several ticks of the simulation.
+// The presses of a frame are reported only in its first tick, so they are not handled more than once.
+
+var (
+	gamepadIDs  []ebiten.GamepadID
+	pressedKeys []ebiten.Key
+	handled     bool                         // The presses of this frame are handled in an earlier tick
+	repeatTimes = map[ebiten.Key]time.Time{} // Time of the next repeat of the held keys
+)
+
+// Update refreshes the connected gamepads and the presses. It must be called once at the beginning of every frame.
+func Update() {
+	gamepadIDs = ebiten.AppendGamepadIDs(gamepadIDs[:0])
+	handled = false
+}
+
+// EndTick must be called at the end of every tick, the presses of the frame are not reported in its later ticks.
+func EndTick() {
+	handled = true
+}
+
+// IsKeyJustPressed returns true if the key is pressed in this tick.
+func IsKeyJustPressed(key ebiten.Key) bool {
+	return !handled && inpututil.IsKeyJustPressed(key)
+}
+
+// IsKeyRepeated returns true when the key is pressed and periodically after the delay while it is held.
+func IsKeyRepeated(key ebiten.Key, delay, interval time.Duration) bool {
+	if handled || !ebiten.IsKeyPressed(key) {
+		return false
+	}
+
+	now := time.Now()
+	if inpututil.IsKeyJustPressed(key) {
+		repeatTimes[key] = now.Add(delay)
+		return true
+	}
+	if next, ok := repeatTimes[key]; ok && !now.Before(next) {
+		repeatTimes[key] = next.Add(interval)
+		return true
+	}
+	return false
+}
+
+// AppendInputChars appends the characters entered in this tick to the runes.
+func AppendInputChars(runes []rune) []rune {
+	if handled {
+		return runes
+	}
+	return ebiten.AppendInputChars(runes)
+}
+
+// IsActionJustPressed returns true if one of the keys or buttons bound to the action is pressed in this tick.
+func IsActionJustPressed(action Action) bool {
+	if handled {
+		return false
+	}
+
+	binding := &bindings[action]
+	for _, key := range binding.Keys {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+
+	for _, id := range gamepadIDs {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for _, button := range binding.Buttons {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// JustPressedKey returns the first key pressed in this tick.
+func JustPressedKey() (ebiten.Key, bool) {
+	if handled {
+		return 0, false
+	}
+	pressedKeys = inpututil.AppendPressedKeys(pressedKeys[:0])
+	for _, key := range pressedKeys {
+		if inpututil.IsKeyJustPressed(key) {
+			return key, true
+		}
+	}
+	return 0, false
+}
+
+// JustPressedButton returns the first standard layout gamepad button pressed in this tick.
+func JustPressedButton() (ebiten.StandardGamepadButton, bool) {
+	if handled {
+		return 0, false
+	}
+	for _, id := range gamepadIDs {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for button := ebiten.StandardGamepadButton(0); button <= ebiten.StandardGamepadButtonMax; button++ {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				return button, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// AnyJustPressed returns true if any key or gamepad button is pressed in this tick.
+func AnyJustPressed() bool {
+	if _, ok := JustPressedKey(); ok {
+		return true
+	}
+	_, ok := JustPressedButton()
+	return ok
+}
+
+// KeyLabel returns the short name of the first key bound to the action for the help texts, or "-" if the
+// action has no key.
+func KeyLabel(action Action) string {
+	keys := bindings[action].Keys
+	if len(keys) == 0 {
+		return "-"
+	}
+	if keys[0] == ebiten.KeyEscape {
+		return "Esc"
+	}
+	name := strings.TrimPrefix(keys[0].String(), "Digit")
+	return strings.TrimPrefix(name, "Arrow")
+}
+
+// ButtonName returns a short name of the standard layout gamepad button.
+func ButtonName(button ebiten.StandardGamepadButton) string {
+	if int(button) < len(buttonNames) {
+		return buttonNames[button]
+	}
+	return "Pad?"
+}
+
+var buttonNames = [...]string{
+	ebiten.StandardGamepadButtonRightBottom:      "Pad A",
+	ebiten.StandardGamepadButtonRightRight:       "Pad B",
+	ebiten.StandardGamepadButtonRightLeft:        "Pad X",
+	ebiten.StandardGamepadButtonRightTop:         "Pad Y",
+	ebiten.StandardGamepadButtonFrontTopLeft:     "Pad LB",
+	ebiten.StandardGamepadButtonFrontTopRight:    "Pad RB",
+	ebiten.StandardGamepadButtonFrontBottomLeft:  "Pad LT",
+	ebiten.StandardGamepadButtonFrontBottomRight: "Pad RT",
+	ebiten.StandardGamepadButtonCenterLeft:       "Pad Back",
+	ebiten.StandardGamepadButtonCenterRight:      "Pad Start",
+	ebiten.StandardGamepadButtonLeftStick:        "Pad LS",
+	ebiten.StandardGamepadButtonRightStick:       "Pad RS",
+	ebiten.StandardGamepadButtonLeftTop:          "Pad Up",
+	ebiten.StandardGamepadButtonLeftBottom:       "Pad Down",
+	ebiten.StandardGamepadButtonLeftLeft:         "Pad Left",
+	ebiten.StandardGamepadButtonLeftRight:        "Pad Right",
+	ebiten.StandardGamepadButtonCenterCenter:     "Pad Home",
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"image/color"
+	"math"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/object"
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Minimap and grid parameters
+const (
+	minimapMaxSize     = 180 // Length of the longer side of the minimap
+	minimapMargin      = 10
+	minimapFoodSize    = 4
+	minimapBorderAlpha = 48
+	gridSpacing        = 120
+	gridAlpha          = 24
+)
+
+// worldScrolls returns true if the world is larger than the screen.
+func worldScrolls() bool {
+	return (param.WorldWidth > param.ScreenWidth) || (param.WorldHeight > param.ScreenHeight)
+}
+
+// drawGrid draws lines at fixed world positions so that the scrolling is visible on a plain background.
+func drawGrid(screen *ebiten.Image) {
+	clr := fadeColor(param.ColorDebug, gridAlpha)
+	screenWidth, screenHeight := float64(param.ScreenWidth), float64(param.ScreenHeight)
+	camPos := c.Cam.RenderPos()
+
+	for x := 0; x < param.WorldWidth; x += gridSpacing {
+		screenX := wrapPos(float64(x)-float64(camPos.X), float64(param.WorldWidth))
+		if screenX < screenWidth {
+			ebitenutil.DrawLine(screen, screenX, 0, screenX, screenHeight, clr)
+		}
+	}
+	for y := 0; y < param.WorldHeight; y += gridSpacing {
+		screenY := wrapPos(float64(y)-float64(camPos.Y), float64(param.WorldHeight))
+		if screenY < screenHeight {
+			ebitenutil.DrawLine(screen, 0, screenY, screenWidth, screenY, clr)
+		}
+	}
+}
+
+// drawMinimap draws the whole world scaled down to the bottom right corner of the screen.
+func drawMinimap(screen *ebiten.Image, snakes []*s.Snake, food *object.Food) {
+	scale := minimapMaxSize / math.Max(float64(param.WorldWidth), float64(param.WorldHeight))
+	size := c.Vec64{X: float64(param.WorldWidth) * scale, Y: float64(param.WorldHeight) * scale}
+	pos := c.Vec64{
+		X: float64(param.ScreenWidth) - size.X - minimapMargin,
+		Y: float64(param.ScreenHeight) - size.Y - minimapMargin,
+	}
+	drawRect := func(rect c.RectF32, clr color.Color) {
+		rectPos, rectSize := rect.Pos.To64(), rect.Size.To64()
+		ebitenutil.DrawRect(screen, pos.X+rectPos.X*scale, pos.Y+rectPos.Y*scale,
+			math.Max(rectSize.X*scale, 1), math.Max(rectSize.Y*scale, 1), clr)
+	}
+
+	// Draw the world area
+	ebitenutil.DrawRect(screen, pos.X, pos.Y, size.X, size.Y, fadeColor(param.ColorBackground, 255))
+	ebitenutil.DrawRect(screen, pos.X, pos.Y, size.X, size.Y, fadeColor(param.ColorDebug, minimapBorderAlpha))
+
+	// Draw the camera view, it is split at the world edges like the game objects.
+	var view c.TeleComp
+	view.Update(&c.RectF32{
+		Pos:  c.Cam.RenderPos(),
+		Size: c.Vec32{X: float32(param.ScreenWidth), Y: float32(param.ScreenHeight)},
+	})
+	for iRect := uint8(0); iRect < view.NumRects; iRect++ {
+		drawRect(view.Rects[iRect], fadeColor(param.ColorDebug, minimapBorderAlpha))
+	}
+
+	// Draw the snakes
+	for _, snake := range snakes {
+		for unit := snake.UnitHead; unit != nil; unit = unit.Next {
+			for iRect := uint8(0); iRect < unit.CompCollision.NumRects; iRect++ {
+				drawRect(unit.CompCollision.Rects[iRect], param.ColorSnake1)
+			}
+		}
+	}
+
+	// Draw the food
+	foodPos := food.Center.To64()
+	ebitenutil.DrawRect(screen, pos.X+foodPos.X*scale-minimapFoodSize/2.0, pos.Y+foodPos.Y*scale-minimapFoodSize/2.0,
+		minimapFoodSize, minimapFoodSize, param.ColorFood)
+}
+
+// fadeColor returns the premultiplied color with the given alpha.
+func fadeColor(clr color.RGBA, alpha uint8) color.RGBA {
+	return color.RGBA{
+		R: uint8(uint16(clr.R) * uint16(alpha) / 255),
+		G: uint8(uint16(clr.G) * uint16(alpha) / 255),
+		B: uint8(uint16(clr.B) * uint16(alpha) / 255),
+		A: alpha,
+	}
+}
+
+func wrapPos(pos, size float64) float64 {
+	pos = math.Mod(pos, size)
+	if pos < 0 {
+		pos += size
+	}
+	return pos
+}
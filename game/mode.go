@@ -0,0 +1,147 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"math"
+
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+type modeID int
+
+const (
+	modeClassic modeID = iota
+	modeTimeAttack
+	modeSurvival
+	modeZen
+	modeTotal
+)
+
+// Mode parameters
+const (
+	timeAttackLimit   = 120.0 // seconds
+	timeAttackWarning = 10.0  // The countdown is highlighted in the last seconds
+	countdownShiftY   = 8
+	survivalAccel     = 2.5 // Speed increase per second
+	survivalSpeedMax  = 600.0
+)
+
+// gameMode holds the rules of a game mode. The game scene calls the rules that are not nil.
+type gameMode struct {
+	id          string // Key of the mode in the settings, the replays and the logs
+	name        string
+	action      input.Action // Title menu action that starts the mode
+	textEnd     string       // Title of the summary when the mode ends the game without a collision
+	endByKey    bool         // The back action ends the game, since the mode has no death
+	passThrough bool         // The snake passes through itself
+	speedMax    float64      // Speed the snake approaches in the mode, the final speed of the speed curve if zero
+	start       func(g *gameScene)
+	step        func(g *gameScene) // Called at the start of every simulation step
+	drawHUD     func(g *gameScene, screen *ebiten.Image)
+}
+
+var gameModes = [modeTotal]gameMode{
+	modeClassic: {
+		id:     "classic",
+		name:   "Classic",
+		action: input.ActionPlayClassic,
+	},
+	modeTimeAttack: {
+		id:      "timeAttack",
+		name:    "Time Attack",
+		action:  input.ActionPlayTimeAttack,
+		textEnd: "Time's Up",
+		step:    stepTimeAttack,
+		drawHUD: drawCountdown,
+	},
+	modeSurvival: {
+		id:       "survival",
+		name:     "Survival",
+		action:   input.ActionPlaySurvival,
+		speedMax: survivalSpeedMax,
+		start:    startSurvival,
+		step:     stepSurvival,
+	},
+	modeZen: {
+		id:          "zen",
+		name:        "Zen",
+		action:      input.ActionPlayZen,
+		endByKey:    true,
+		passThrough: true,
+	},
+}
+
+// stepTimeAttack ends the game when the time is up.
+func stepTimeAttack(g *gameScene) {
+	if g.timeAlive() >= timeAttackLimit {
+		g.endGame()
+	}
+}
+
+// drawCountdown draws the remaining time of the time attack at the top of the screen.
+func drawCountdown(g *gameScene, screen *ebiten.Image) {
+	timeLeft := math.Max(timeAttackLimit-g.timeAlive(), 0)
+	msg := formatDuration(timeLeft)
+	clr := param.ColorScore
+	if timeLeft < timeAttackWarning {
+		clr = param.ColorFood
+	}
+	bound := text.BoundString(param.FontFaceScore, msg)
+	text.Draw(screen, msg, param.FontFaceScore, (param.ScreenWidth-bound.Size().X)/2-bound.Min.X, countdownShiftY-bound.Min.Y, clr)
+}
+
+// startSurvival fixes the speed of the snake, so that eating does not slow it down.
+func startSurvival(g *gameScene) {
+	g.snake.FixedSpeed = true
+}
+
+// stepSurvival speeds up the snake over time.
+func stepSurvival(g *gameScene) {
+	g.snake.Speed = math.Min(param.SnakeSpeedInitial+survivalAccel*g.timeAlive(), survivalSpeedMax)
+}
+
+// findMode returns the mode with the id, or nil if there is none.
+func findMode(id string) *gameMode {
+	for iMode := range gameModes {
+		if gameModes[iMode].id == id {
+			return &gameModes[iMode]
+		}
+	}
+	return nil
+}
+
+// speedFinal returns the speed the snake approaches in the mode.
+func (m *gameMode) speedFinal() float64 {
+	if m.speedMax > 0 {
+		return m.speedMax
+	}
+	return param.SnakeSpeedFinal
+}
+
+// startMode applies the rules of the mode to a new game.
+func (g *gameScene) startMode() {
+	if g.mode.start != nil {
+		g.mode.start(g)
+	}
+}
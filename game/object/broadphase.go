@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package object
+
+import (
+	"github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+// Broadphase parameters
+const broadphaseCellSize = 64
+
+// Broadphase is a uniform grid over the world that finds the collidables near each other before the
+// exact collision tests. The collidables are registered every tick with their collision rectangles, which
+// are already split at the world edges, so an object wrapping around an edge is found from both sides.
+// The buffers keep their capacity between the ticks.
+type Broadphase struct {
+	numCols, numRows int
+	cells            [][]int32 // Ids of the objects overlapping each cell
+	objects          []Collidable
+	marks            []uint32 // Query stamp of each object, so it is reported once per query
+	stamp            uint32
+	ids              []int32
+}
+
+func NewBroadphase() *Broadphase {
+	numCols := (param.WorldWidth + broadphaseCellSize - 1) / broadphaseCellSize
+	numRows := (param.WorldHeight + broadphaseCellSize - 1) / broadphaseCellSize
+
+	return &Broadphase{
+		numCols: numCols,
+		numRows: numRows,
+		cells:   make([][]int32, numCols*numRows),
+	}
+}
+
+// Clear removes all the objects. It is called at the start of every tick before the objects are registered.
+func (b *Broadphase) Clear() {
+	for iCell := range b.cells {
+		b.cells[iCell] = b.cells[iCell][:0]
+	}
+	b.objects = b.objects[:0]
+	b.marks = b.marks[:0]
+}
+
+// Insert registers the object to the cells its rectangles overlap.
+func (b *Broadphase) Insert(obj Collidable) {
+	if !obj.CollEnabled() {
+		return
+	}
+
+	id := int32(len(b.objects))
+	b.objects = append(b.objects, obj)
+	b.marks = append(b.marks, 0)
+
+	rects := obj.CollisionRects()
+	for iRect := range rects {
+		b.forEachCell(&rects[iRect], func(iCell int) {
+			// An object is added once to a cell even if its rects share it.
+			if cell := b.cells[iCell]; (len(cell) == 0) || (cell[len(cell)-1] != id) {
+				b.cells[iCell] = append(cell, id)
+			}
+		})
+	}
+}
+
+// Candidates appends the registered objects sharing a cell with the object to dst in the order they are
+// registered. The object itself is not included. Only these objects can collide with it.
+func (b *Broadphase) Candidates(obj Collidable, dst []Collidable) []Collidable {
+	if !obj.CollEnabled() {
+		return dst
+	}
+	return b.candidates(obj.CollisionRects(), obj, dst)
+}
+
+// CandidatesInRects appends the registered objects overlapping the cells of the rectangles to dst in the
+// order they are registered.
+func (b *Broadphase) CandidatesInRects(rects []core.RectF32, dst []Collidable) []Collidable {
+	return b.candidates(rects, nil, dst)
+}
+
+func (b *Broadphase) candidates(rects []core.RectF32, self Collidable, dst []Collidable) []Collidable {
+	b.stamp++
+	b.ids = b.ids[:0]
+	for iRect := range rects {
+		b.forEachCell(&rects[iRect], func(iCell int) {
+			for _, id := range b.cells[iCell] {
+				if (b.marks[id] != b.stamp) && (b.objects[id] != self) {
+					b.marks[id] = b.stamp
+					b.ids = append(b.ids, id)
+				}
+			}
+		})
+	}
+
+	sortIds(b.ids)
+	for _, id := range b.ids {
+		dst = append(dst, b.objects[id])
+	}
+	return dst
+}
+
+// forEachCell calls fn with the index of each cell the rectangle overlaps.
+func (b *Broadphase) forEachCell(rect *core.RectF32, fn func(iCell int)) {
+	if (rect.Size.X <= 0) || (rect.Size.Y <= 0) {
+		return
+	}
+
+	colMin := b.clampCol(int(rect.Pos.X / broadphaseCellSize))
+	colMax := b.clampCol(int((rect.Pos.X + rect.Size.X) / broadphaseCellSize))
+	rowMin := b.clampRow(int(rect.Pos.Y / broadphaseCellSize))
+	rowMax := b.clampRow(int((rect.Pos.Y + rect.Size.Y) / broadphaseCellSize))
+
+	for row := rowMin; row <= rowMax; row++ {
+		for col := colMin; col <= colMax; col++ {
+			fn(row*b.numCols + col)
+		}
+	}
+}
+
+// Objects outside of the world, like the snakes leaving the title scene, are kept in the border cells.
+func (b *Broadphase) clampCol(col int) int {
+	if col < 0 {
+		return 0
+	} else if col >= b.numCols {
+		return b.numCols - 1
+	}
+	return col
+}
+
+func (b *Broadphase) clampRow(row int) int {
+	if row < 0 {
+		return 0
+	} else if row >= b.numRows {
+		return b.numRows - 1
+	}
+	return row
+}
+
+// sortIds sorts the few candidate ids with insertion sort, it does not allocate.
+func sortIds(ids []int32) {
+	for i := 1; i < len(ids); i++ {
+		for j := i; (j > 0) && (ids[j-1] > ids[j]); j-- {
+			ids[j-1], ids[j] = ids[j], ids[j-1]
+		}
+	}
+}
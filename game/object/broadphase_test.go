@@ -0,0 +1,142 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package object
+
+import (
+	"math/rand"
+	"testing"
+
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+// Benchmark parameters, the same as the benchmark scene's first snake count
+const (
+	benchSnakes     = 300
+	benchSetupTicks = 240
+	benchTurnChance = 60 // A snake turns once in this many ticks on average
+)
+
+// newBenchSnakes creates randomly placed snakes and moves them with random turns, so that they have
+// several units and some of them wrap around the world edges.
+func newBenchSnakes(count int) []*s.Snake {
+	rand.Seed(1)
+	param.TeleportEnabled = true
+
+	snakes := make([]*s.Snake, count)
+	for iSnake := range snakes {
+		snakes[iSnake] = s.NewSnakeRandDirLoc(uint16(120+rand.Intn(360)), 150+rand.Float64()*250, &param.ColorSnake1)
+	}
+	for tick := 0; tick < benchSetupTicks; tick++ {
+		for _, snake := range snakes {
+			if rand.Intn(benchTurnChance) == 0 {
+				dirCurrent := snake.LastDirection()
+				dirNew := s.DirectionUp
+				if dirCurrent.IsVertical() {
+					dirNew = s.DirectionLeft
+				}
+				if rand.Intn(2) == 0 {
+					dirNew = dirNew.Opposite()
+				}
+				snake.TurnTo(s.NewTurn(dirCurrent, dirNew), false)
+			}
+			snake.Update(param.MouthAnimStartDistance)
+		}
+	}
+	return snakes
+}
+
+// hitsBruteForce counts the heads touching another snake by testing them against every unit. A head
+// touching a joint touches the capsules of both units, so the heads are counted instead of the units.
+func hitsBruteForce(snakes []*s.Snake) int {
+	var hits int
+	for _, snake := range snakes {
+		head := snake.HeadCircle(param.RadiusSnake)
+		hit := false
+		for _, other := range snakes {
+			if other == snake {
+				continue
+			}
+			for unit := other.UnitHead; (unit != nil) && !hit; unit = unit.Next {
+				_, hit = head.OverlapsCapsule(unit.Capsule())
+			}
+		}
+		if hit {
+			hits++
+		}
+	}
+	return hits
+}
+
+// hitsBroadphase counts the same heads as hitsBruteForce with the candidates of the broadphase.
+func hitsBroadphase(broadphase *Broadphase, snakes []*s.Snake, candidates []Collidable) int {
+	broadphase.Clear()
+	for _, snake := range snakes {
+		for unit := snake.UnitHead; unit != nil; unit = unit.Next {
+			broadphase.Insert(unit)
+		}
+	}
+
+	var hits int
+	for _, snake := range snakes {
+		head := snake.HeadCircle(param.RadiusSnake)
+		candidates = broadphase.Candidates(snake.UnitHead, candidates[:0])
+		for _, candidate := range candidates {
+			unit := candidate.(*s.Unit)
+			if snake.Owns(unit) {
+				continue
+			}
+			if _, overlaps := head.OverlapsCapsule(unit.Capsule()); overlaps {
+				hits++
+				break
+			}
+		}
+	}
+	return hits
+}
+
+func TestBroadphaseMatchesBruteForce(t *testing.T) {
+	snakes := newBenchSnakes(benchSnakes)
+	want := hitsBruteForce(snakes)
+	if got := hitsBroadphase(NewBroadphase(), snakes, nil); got != want {
+		t.Errorf("broadphase found %d heads hitting another snake, brute force found %d", got, want)
+	}
+	if want == 0 {
+		t.Error("the snakes do not touch each other, the test does not compare anything")
+	}
+}
+
+func BenchmarkBroadphase(b *testing.B) {
+	snakes := newBenchSnakes(benchSnakes)
+	broadphase := NewBroadphase()
+	var candidates []Collidable
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hitsBroadphase(broadphase, snakes, candidates)
+	}
+}
+
+func BenchmarkBruteForce(b *testing.B) {
+	snakes := newBenchSnakes(benchSnakes)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hitsBruteForce(snakes)
+	}
+}
@@ -47,7 +47,8 @@ type Food struct {
 	Center   c.Vec32
 }
 
-func newFood(center c.Vec32) *Food {
+// NewFood creates an inactive food at the center. It is activated if it does not overlap with a snake.
+func NewFood(center c.Vec32) *Food {
 	newFood := &Food{
 		Center: center,
 	}
@@ -68,7 +69,7 @@ func newFood(center c.Vec32) *Food {
 }
 
 func NewFoodRandLoc() *Food {
-	return newFood(c.VecI{X: rand.Intn(param.ScreenWidth), Y: rand.Intn(param.ScreenHeight)}.To32())
+	return NewFood(c.VecI{X: rand.Intn(param.WorldWidth), Y: rand.Intn(param.WorldHeight)}.To32())
 }
 
 func (f Food) Draw(dst *ebiten.Image) {
@@ -76,12 +77,17 @@ func (f Food) Draw(dst *ebiten.Image) {
 	dst.DrawTriangles(vertices, indices, imageFood, &foodDrawOpts)
 }
 
-// Implement collidable interface
+// Implement Collidable interface
 // ------------------------------
 func (f Food) CollEnabled() bool {
 	return true
 }
 
 func (f Food) CollisionRects() []c.RectF32 {
-	return f.Rects[:]
+	return f.Rects[:f.NumRects]
+}
+
+// Circle returns the shape of the food.
+func (f Food) Circle() c.Circle {
+	return c.Circle{Center: f.Center.To64(), Radius: param.RadiusFood}
 }
@@ -0,0 +1,226 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package object
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/shader"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Particle parameters
+const (
+	particlesMax      = 2048
+	particleImageSize = 16
+	particleDrag      = 2.5 // Velocity decay rate [1/s]
+	maxVertices       = math.MaxUint16
+)
+
+// Emitter parameters
+const (
+	burstCount      = 24
+	burstSpeedMin   = 60.0
+	burstSpeedDiff  = 160.0
+	burstSize       = 6.0
+	burstLife       = 0.6 // seconds
+	shatterSpacing  = 5.0 // Distance between the particles along the snake
+	shatterSpeedMin = 20.0
+	shatterSpeedDif = 140.0
+	shatterSize     = param.SnakeWidth / 2.5
+	shatterLife     = 1.2
+	dustInterval    = 0.04 // seconds
+	dustSpeed       = 15.0
+	dustSize        = 5.0
+	dustLife        = 0.8
+	dustAlpha       = 0.35
+)
+
+var imageParticle = ebiten.NewImage(particleImageSize, particleImageSize)
+
+func init() {
+	imageParticle.DrawRectShader(particleImageSize, particleImageSize, &shader.Circle, &ebiten.DrawRectShaderOptions{
+		Uniforms: map[string]interface{}{
+			"Radius": float32(particleImageSize / 2.0),
+		},
+	})
+}
+
+type particle struct {
+	pos     c.Vec32
+	vel     c.Vec32
+	size    float32
+	life    float32 // Remaining life in seconds
+	lifeMax float32
+	alpha   float32 // Alpha at the start of the life
+	clr     *color.RGBA
+}
+
+// Particles is a particle system that draws all of its particles with a single DrawTriangles call.
+type Particles struct {
+	particles []particle
+	vertices  []ebiten.Vertex
+	indices   []uint16
+	comp      c.TeleCompTriang // Splits a particle at the world edges and moves it to the screen
+	drawOpts  ebiten.DrawTrianglesOptions
+	dustTime  float32
+	movedTick uint64 // Simulation tick of the last update
+}
+
+func NewParticles() *Particles {
+	return &Particles{
+		particles: make([]particle, 0, particlesMax),
+	}
+}
+
+func (p *Particles) emit(pos c.Vec32, vel c.Vec32, size, life, alpha float32, clr *color.RGBA) {
+	if len(p.particles) >= particlesMax {
+		return
+	}
+
+	p.particles = append(p.particles, particle{
+		pos:     pos,
+		vel:     vel,
+		size:    size,
+		life:    life,
+		lifeMax: life,
+		alpha:   alpha,
+		clr:     clr,
+	})
+}
+
+// EmitBurst throws particles out of the center in all directions.
+func (p *Particles) EmitBurst(center c.Vec32, clr *color.RGBA) {
+	for iParticle := 0; iParticle < burstCount; iParticle++ {
+		p.emit(center, randVelocity(burstSpeedMin, burstSpeedDiff), burstSize*(0.5+rand.Float32()), burstLife*(0.5+rand.Float32()), 1, clr)
+	}
+}
+
+// EmitShatter breaks the body of the snake into particles.
+func (p *Particles) EmitShatter(snake *s.Snake) {
+	for unit := snake.UnitHead; unit != nil; unit = unit.Next {
+		head, back := unit.HeadCenter, unit.BackCenter()
+		length := c.Distance(head, back)
+		for dist := 0.0; dist <= length; dist += shatterSpacing {
+			ratio := 0.0
+			if length > 0 {
+				ratio = dist / length
+			}
+			pos := c.Vec64{X: head.X + (back.X-head.X)*ratio, Y: head.Y + (back.Y-head.Y)*ratio}
+			p.emit(pos.To32(), randVelocity(shatterSpeedMin, shatterSpeedDif), shatterSize*(0.6+0.8*rand.Float32()),
+				shatterLife*(0.5+rand.Float32()), 1, snake.Color())
+		}
+	}
+}
+
+// EmitDust leaves a faint dust trail at the position. It is called every tick and emits at a fixed interval.
+func (p *Particles) EmitDust(pos c.Vec64, clr *color.RGBA) {
+	p.dustTime += float32(param.DeltaTime)
+	for ; p.dustTime >= dustInterval; p.dustTime -= dustInterval {
+		p.emit(pos.To32(), randVelocity(0, dustSpeed), dustSize*(0.5+rand.Float32()), dustLife, dustAlpha, clr)
+	}
+}
+
+func randVelocity(speedMin, speedDiff float32) c.Vec32 {
+	angle := rand.Float64() * 2 * math.Pi
+	speed := speedMin + rand.Float32()*speedDiff
+	return c.Vec32{X: speed * float32(math.Cos(angle)), Y: speed * float32(math.Sin(angle))}
+}
+
+func (p *Particles) Update() {
+	deltaTime := float32(param.DeltaTime)
+	drag := float32(math.Exp(-particleDrag * param.DeltaTime))
+
+	alive := p.particles[:0]
+	for _, particle := range p.particles {
+		particle.life -= deltaTime
+		if particle.life <= 0 {
+			continue
+		}
+
+		particle.vel.X *= drag
+		particle.vel.Y *= drag
+		particle.pos.X += particle.vel.X * deltaTime
+		particle.pos.Y += particle.vel.Y * deltaTime
+		if param.TeleportEnabled {
+			particle.pos.X = c.Wrap(particle.pos.X, float32(param.WorldWidth))
+			particle.pos.Y = c.Wrap(particle.pos.Y, float32(param.WorldHeight))
+		}
+
+		alive = append(alive, particle)
+	}
+	p.particles = alive
+	p.movedTick = param.SimTicks
+}
+
+// Draw batches the visible parts of all particles into one draw call.
+func (p *Particles) Draw(dst *ebiten.Image) {
+	p.vertices = p.vertices[:0]
+	p.indices = p.indices[:0]
+
+	// Draw the particles between their last two positions, they move by their velocity in a step.
+	var lag float32
+	if p.movedTick == param.StepTick {
+		lag = float32((1 - param.RenderAlpha) * param.DeltaTime)
+	}
+
+	for iParticle := range p.particles {
+		particle := &p.particles[iParticle]
+		halfSize := particle.size / 2.0
+		p.comp.Update(&c.RectF32{
+			Pos: c.Vec32{
+				X: particle.pos.X - particle.vel.X*lag - halfSize,
+				Y: particle.pos.Y - particle.vel.Y*lag - halfSize,
+			},
+			Size: c.Vec32{X: particle.size, Y: particle.size},
+		})
+
+		// Fade out and scale the color, the source image is premultiplied.
+		alpha := particle.alpha * particle.life / particle.lifeMax * float32(particle.clr.A) / 255.0
+		clrR := float32(particle.clr.R) / 255.0 * alpha
+		clrG := float32(particle.clr.G) / 255.0 * alpha
+		clrB := float32(particle.clr.B) / 255.0 * alpha
+		srcScale := particleImageSize / particle.size
+
+		vertices, _ := p.comp.Triangles()
+		if len(p.vertices)+len(vertices) > maxVertices {
+			break
+		}
+		for iQuad := 0; iQuad < len(vertices); iQuad += 4 {
+			offset := uint16(len(p.vertices))
+			for _, vertex := range vertices[iQuad : iQuad+4] {
+				vertex.SrcX *= srcScale
+				vertex.SrcY *= srcScale
+				vertex.ColorR, vertex.ColorG, vertex.ColorB, vertex.ColorA = clrR, clrG, clrB, alpha
+				p.vertices = append(p.vertices, vertex)
+			}
+			p.indices = append(p.indices, offset, offset+2, offset+1, offset+1, offset+2, offset+3)
+		}
+	}
+
+	if len(p.indices) > 0 {
+		dst.DrawTriangles(p.vertices, p.indices, imageParticle, &p.drawOpts)
+	}
+}
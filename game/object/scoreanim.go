@@ -31,49 +31,56 @@ import (
 )
 
 const (
-	decrementAlpha   = 8.0
-	scoreAnimSpeed   = 25
-	scoreAnimPadding = 8
+	scoreAnimFadeRate = 8.0 * 60 // Alpha decrease per second
+	scoreAnimSpeed    = 25
+	scoreAnimPadding  = 8
 )
 
-var (
-	scoreAnimShiftY    float32
-	scoreAnimBoundSize c.Vec32
-	scoreAnimImage     *ebiten.Image
-)
+// Images of the earned points are rendered once for each value.
+var scoreAnimImages = make(map[int]*ebiten.Image)
 
 type ScoreAnim struct {
 	c.TeleCompTriang
 	pos       c.Vec32
-	alpha     uint8
+	size      c.Vec32
+	alpha     float32
 	direction s.DirectionT
+	image     *ebiten.Image
 	drawOpts  ebiten.DrawTrianglesOptions
 }
 
+// InitScoreAnim drops the rendered images, so the points are rendered again with the current score font.
 func InitScoreAnim() {
-	// Init animation text bound variables
-	foodScoreMsg := strconv.Itoa(param.FoodScore)
-	scoreAnimBound := text.BoundString(param.FontFaceScore, foodScoreMsg)
-	scoreAnimBoundSizeI := scoreAnimBound.Size()
-	scoreAnimBoundSize.X = float32(scoreAnimBoundSizeI.X)
-	scoreAnimBoundSize.Y = float32(scoreAnimBoundSizeI.Y)
-	scoreAnimShiftY = param.RadiusSnake + scoreAnimBoundSize.Y/2.0 + scoreAnimPadding
-
-	// Prepare score animation text image.
-	scoreAnimImage = ebiten.NewImage(scoreAnimBoundSizeI.X, scoreAnimBoundSizeI.Y)
-	text.Draw(scoreAnimImage, foodScoreMsg, param.FontFaceScore,
-		-scoreAnimBound.Min.X, -scoreAnimBound.Min.Y,
-		color.White)
+	scoreAnimImages = make(map[int]*ebiten.Image)
 }
 
-func NewScoreAnim(pos c.Vec32) *ScoreAnim {
+// scoreAnimImage returns the image of the points.
+func scoreAnimImage(points int) *ebiten.Image {
+	if img, ok := scoreAnimImages[points]; ok {
+		return img
+	}
+
+	msg := strconv.Itoa(points)
+	bound := text.BoundString(param.FontFaceScore, msg)
+	img := ebiten.NewImage(bound.Dx(), bound.Dy())
+	text.Draw(img, msg, param.FontFaceScore, -bound.Min.X, -bound.Min.Y, color.White)
+	scoreAnimImages[points] = img
+	return img
+}
+
+// NewScoreAnim creates an animation of the points earned at the position.
+func NewScoreAnim(pos c.Vec32, points int) *ScoreAnim {
+	img := scoreAnimImage(points)
+	size := img.Bounds().Size()
 	newAnim := &ScoreAnim{
-		pos: c.Vec32{
-			X: pos.X,
-			Y: pos.Y - scoreAnimShiftY,
-		},
-		alpha:     param.ColorScore.A,
+		size:      c.Vec32{X: float32(size.X), Y: float32(size.Y)},
+		alpha:     float32(param.ColorScore.A),
 		direction: s.DirectionUp,
+		image:     img,
+	}
+	newAnim.pos = c.Vec32{
+		X: pos.X,
+		Y: pos.Y - (param.RadiusSnake + newAnim.size.Y/2.0 + scoreAnimPadding),
 	}
 	newAnim.SetColor(&param.ColorScore)
 
@@ -86,10 +93,10 @@ func (s *ScoreAnim) createRects() {
 	// Create a rectangle to be split
 	pureRect := c.RectF32{
 		Pos: c.Vec32{
-			X: s.pos.X - scoreAnimBoundSize.X/2.0,
-			Y: s.pos.Y - scoreAnimBoundSize.Y/2.0,
+			X: s.pos.X - s.size.X/2.0,
+			Y: s.pos.Y - s.size.Y/2.0,
 		},
-		Size: c.Vec32{X: scoreAnimBoundSize.X, Y: scoreAnimBoundSize.Y},
+		Size: s.size,
 	}
 	// Split this rectangle if it is on a screen edge.
 	s.TeleCompTriang.Update(&pureRect)
@@ -98,15 +105,15 @@ func (s *ScoreAnim) createRects() {
 // Returns true when the animation is finished
 func (s *ScoreAnim) Update() bool {
 	// Move animation
-	s.pos.Y -= scoreAnimSpeed * param.DeltaTime
+	s.pos.Y -= scoreAnimSpeed * float32(param.DeltaTime)
 
 	// Decrease alpha
-	if s.alpha < decrementAlpha {
+	s.alpha -= scoreAnimFadeRate * float32(param.DeltaTime)
+	if s.alpha <= 0 {
 		return true
 	}
 
-	s.alpha -= decrementAlpha
-	color := color.RGBA{param.ColorScore.R, param.ColorScore.G, param.ColorScore.B, s.alpha}
+	color := color.RGBA{param.ColorScore.R, param.ColorScore.G, param.ColorScore.B, uint8(s.alpha)}
 	s.SetColor(&color)
 
 	// Update rectangles of this anim
@@ -117,5 +124,5 @@ func (s *ScoreAnim) Update() bool {
 
 func (s *ScoreAnim) Draw(dst *ebiten.Image) {
 	vertices, indices := s.Triangles()
-	dst.DrawTriangles(vertices, indices, scoreAnimImage, &s.drawOpts)
+	dst.DrawTriangles(vertices, indices, s.image, &s.drawOpts)
 }
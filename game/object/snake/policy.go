@@ -0,0 +1,36 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package snake
+
+import "github.com/anilkonac/snake-ebiten/game/param"
+
+// TurnPolicy decides how the turn inputs of a snake are buffered and queued.
+type TurnPolicy struct {
+	MaxQueueDepth int     `json:"maxQueueDepth"` // Turns exceeding this depth are dropped. 0 means unlimited.
+	StaleAfterMs  int     `json:"staleAfterMs"`  // Queued turns older than this are dropped. 0 means never.
+	PreTurnWindow float64 `json:"preTurnWindow"` // How many pixels before a turn becomes safe it can be queued.
+	UTurnMacro    bool    `json:"uTurnMacro"`    // Turn back with two quick turns when the reverse direction is pressed.
+	BufferInputs  bool    `json:"bufferInputs"`  // Take every direction pressed in a tick instead of the first one.
+}
+
+// DefaultTurnPolicy is the policy of the snakes unless another one is given.
+var DefaultTurnPolicy = TurnPolicy{
+	PreTurnWindow: param.SnakeWidth,
+}
@@ -0,0 +1,320 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package snake
+
+import (
+	"image/color"
+	"math"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/shader"
+	"github.com/anilkonac/snake-ebiten/game/skin"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Snake parts in the red channel of the skin shader's vertex colors
+const (
+	partBody = iota
+	partHead
+	partJoint
+	partTail
+	partArcLeft  // Quarter annulus of a left turn
+	partArcRight // Quarter annulus of a right turn
+)
+
+// Color uniforms of the skin shader
+const (
+	skinColorHead = iota
+	skinColorTail
+	skinColorPattern
+	skinColorEye
+	skinColorPupil
+	skinColorTotal
+)
+
+var (
+	shaderSkin = shader.New(shader.PathSkin)
+
+	skinPatterns = map[string]float32{
+		skin.PatternNone:    0,
+		skin.PatternStripes: 1,
+		skin.PatternDots:    2,
+		skin.PatternScales:  3,
+	}
+	skinTailTips = map[string]float32{
+		skin.TailRound:   0,
+		skin.TailPointed: 1,
+		skin.TailFade:    2,
+	}
+	directionVectors = [DirectionTotal]c.Vec32{
+		DirectionUp:    {X: 0, Y: -1},
+		DirectionDown:  {X: 0, Y: 1},
+		DirectionLeft:  {X: -1, Y: 0},
+		DirectionRight: {X: 1, Y: 0},
+	}
+)
+
+// drawSkin draws the snake with its skin in a single batch. Without rounded turns, the joints are disks drawn first
+// so that the bodies cover them with their patterns. With rounded turns, the bodies stop a radius
+// away from the turns and the joints are quarter annuli drawn between them. The tail tip and the
+// head are drawn on top.
+func (s *Snake) drawSkin(dst *ebiten.Image) {
+	totalLength := math.Max(s.Length()-param.SnakeWidth, 1)
+	s.updateSkinUniforms(totalLength)
+
+	if !RoundedTurns {
+		s.drawJoints(dst)
+	}
+
+	// Draw the bodies. The material coordinate of a body's front end is fixed to the distance traveled,
+	// so the pattern moves with the snake and stays continuous across the splits at the world edges.
+	// The material range in the green and blue channels clips the body ends covered by the arcs.
+	headMaterial := s.headMaterial()
+	var distFromHead float64
+	for unit := s.UnitHead; unit != nil; unit = unit.Next {
+		unit := unit
+		frontMaterial := float32(headMaterial - distFromHead)
+		trimFront, trimBack := s.bodyTrims(unit)
+		materialMax := frontMaterial - trimFront
+		materialMin := frontMaterial - unit.bodyLength() + trimBack
+		if materialMax > materialMin {
+			s.drawSkinPart(dst, &unit.CompBody, func(vertex *ebiten.Vertex) {
+				along, across := unit.bodyCoords(vertex.SrcX, vertex.SrcY)
+				vertex.SrcX = frontMaterial - along
+				vertex.SrcY = across
+				setVertexPart(vertex, partBody, materialMax, materialMin, 0)
+			})
+		}
+		distFromHead += unit.length
+	}
+
+	if RoundedTurns {
+		s.drawArcs(dst)
+	}
+
+	s.drawSkinPart(dst, &s.unitTail.CompTriangTail, func(vertex *ebiten.Vertex) {
+		setVertexPart(vertex, partTail, 0, 0, 0)
+	})
+	s.drawSkinPart(dst, &s.UnitHead.CompTriangHead, func(vertex *ebiten.Vertex) {
+		setVertexPart(vertex, partHead, 0, 0, 0)
+	})
+	s.flushSkin(dst)
+}
+
+// drawJoints draws disks at the turns with the material coordinate of their centers.
+func (s *Snake) drawJoints(dst *ebiten.Image) {
+	headMaterial := s.headMaterial()
+	distFromHead := s.UnitHead.length
+	for unit := s.UnitHead.Next; unit != nil; unit = unit.Next {
+		material := float32(headMaterial - distFromHead)
+		s.drawSkinPart(dst, &unit.CompTriangHead, func(vertex *ebiten.Vertex) {
+			setVertexPart(vertex, partJoint, material, 0, 0)
+		})
+		distFromHead += unit.length
+	}
+}
+
+// drawArcs draws a quarter annulus at each turn. Its center is the inner corner of the turn, and its
+// square is the one of the joint disk, so the splits at the world edges are the same. Each vertex gets
+// its position in the arc's frame: the distance from the center towards the snake's head on the
+// incoming unit and towards its tail on the outgoing unit. When a neighbour unit is shorter than the
+// radius, the arc is clipped where that unit ends, so it does not stick out of the snake.
+func (s *Snake) drawArcs(dst *ebiten.Image) {
+	headMaterial := s.headMaterial()
+	distFromHead := s.UnitHead.length
+	for unit := s.UnitHead.Next; unit != nil; unit = unit.Next {
+		material := float32(headMaterial - distFromHead)
+		dirIn, dirOut := directionVectors[unit.Direction], directionVectors[unit.prev.Direction]
+
+		part := float32(partArcRight)
+		if isTurnLeft(unit.Direction, unit.prev.Direction) {
+			part = partArcLeft
+		}
+
+		extentIn := unit.length // Distance from the turn to the end of the incoming unit
+		if unit.Next == nil {
+			extentIn -= param.SnakeWidth
+		}
+		clipIn := float32(math.Max(param.RadiusSnake-extentIn, 0))
+		clipOut := float32(math.Max(param.RadiusSnake-unit.prev.length, 0))
+
+		s.drawSkinPart(dst, &unit.CompTriangHead, func(vertex *ebiten.Vertex) {
+			// Position relative to the arc center: P - R*dirIn + R*dirOut
+			x := vertex.SrcX - param.RadiusSnake + param.RadiusSnake*(dirIn.X-dirOut.X)
+			y := vertex.SrcY - param.RadiusSnake + param.RadiusSnake*(dirIn.Y-dirOut.Y)
+			vertex.SrcX = x*dirIn.X + y*dirIn.Y
+			vertex.SrcY = -(x*dirOut.X + y*dirOut.Y)
+			setVertexPart(vertex, part, material, clipIn, clipOut)
+		})
+		distFromHead += unit.length
+	}
+}
+
+// bodyTrims returns how much of the body is covered by the arcs at its front and back ends.
+func (s *Snake) bodyTrims(unit *Unit) (front, back float32) {
+	if !RoundedTurns {
+		return 0, 0
+	}
+	if unit != s.UnitHead {
+		front = param.RadiusSnake
+	}
+	if unit.Next != nil {
+		back = param.RadiusSnake
+	}
+	return
+}
+
+// visibleBodyLength returns the length of the unit's body that is not covered by the arcs.
+func (s *Snake) visibleBodyLength(unit *Unit) float32 {
+	front, back := s.bodyTrims(unit)
+	if length := unit.bodyLength() - front - back; length > 0 {
+		return length
+	}
+	return 0
+}
+
+// drawSkinPart appends the triangles of a component to the skin batch after setting their attributes.
+// The batch is drawn with a single call when the snake is complete, or earlier if it is full.
+func (s *Snake) drawSkinPart(dst *ebiten.Image, comp *c.TeleCompTriang, setAttributes func(*ebiten.Vertex)) {
+	vertices, indices := comp.Triangles()
+	if len(indices) == 0 {
+		return
+	}
+
+	if (len(s.indices)+len(indices) > ebiten.MaxIndicesCount) || (len(s.vertices)+len(vertices) > math.MaxUint16) {
+		s.flushSkin(dst)
+	}
+
+	offset := uint16(len(s.vertices))
+	for iVertex := range vertices {
+		s.vertices = append(s.vertices, vertices[iVertex])
+		setAttributes(&s.vertices[len(s.vertices)-1])
+	}
+	for _, index := range indices {
+		s.indices = append(s.indices, index+offset)
+	}
+}
+
+// flushSkin draws the skin batch and empties it. The buffers keep their capacity for the next frame.
+func (s *Snake) flushSkin(dst *ebiten.Image) {
+	if len(s.indices) > 0 {
+		dst.DrawTrianglesShader(s.vertices, s.indices, shaderSkin, &s.drawOptsSkin)
+	}
+	s.vertices = s.vertices[:0]
+	s.indices = s.indices[:0]
+}
+
+// initSkinUniforms creates the color uniforms once, they are updated in place.
+func (s *Snake) initSkinUniforms() {
+	uniforms := s.drawOptsSkin.Uniforms
+	uniforms["HeadColor"] = s.skinColors[skinColorHead][:]
+	uniforms["TailColor"] = s.skinColors[skinColorTail][:]
+	uniforms["PatternColor"] = s.skinColors[skinColorPattern][:]
+	uniforms["EyeColor"] = s.skinColors[skinColorEye][:]
+	uniforms["PupilColor"] = s.skinColors[skinColorPupil][:]
+}
+
+func (s *Snake) updateSkinUniforms(totalLength float64) {
+	sk := s.Skin
+	headColor, tailColor := color.RGBA(sk.HeadColor), color.RGBA(sk.TailColor)
+	if sk.ThemeColors {
+		headColor, tailColor = *s.color, *s.color
+	}
+	setColorVec4(&s.skinColors[skinColorHead], headColor)
+	setColorVec4(&s.skinColors[skinColorTail], tailColor)
+	setColorVec4(&s.skinColors[skinColorPattern], color.RGBA(sk.PatternColor))
+	setColorVec4(&s.skinColors[skinColorEye], color.RGBA(sk.EyeColor))
+	setColorVec4(&s.skinColors[skinColorPupil], color.RGBA(sk.PupilColor))
+
+	proxToFood := s.proxToFood
+	if !MouthEnabled {
+		proxToFood = 0
+	}
+
+	uniforms := s.drawOptsSkin.Uniforms
+	uniforms["Direction"] = float32(s.UnitHead.Direction)
+	uniforms["ProxToFood"] = proxToFood
+	uniforms["TailDirection"] = float32(s.unitTail.Direction)
+	uniforms["Traveled"] = float32(s.headMaterial())
+	uniforms["TotalLength"] = float32(totalLength)
+	uniforms["HeadBody"] = s.visibleBodyLength(s.UnitHead)
+	uniforms["TailBody"] = s.visibleBodyLength(s.unitTail)
+	uniforms["Pattern"] = skinPatterns[sk.Pattern]
+	uniforms["PatternPeriod"] = float32(s.patternPeriod())
+	uniforms["PatternWidth"] = sk.PatternWidth
+	uniforms["Shading"] = sk.Shading
+	uniforms["Eyes"] = boolFloat(sk.Eyes)
+	uniforms["TailTip"] = skinTailTips[sk.TailTip]
+}
+
+// patternPeriod returns the length of a pattern cell of the skin.
+func (s *Snake) patternPeriod() float64 {
+	return math.Max(float64(s.Skin.PatternPeriod), 1)
+}
+
+// headMaterial returns the material coordinate of the head center. It is the distance traveled modulo the
+// pattern period, so it stays small enough for the float32 precision of the shader. The pattern repeats
+// with the period and the gradient uses only the differences, so they do not change.
+func (s *Snake) headMaterial() float64 {
+	return math.Mod(s.traveled, s.patternPeriod())
+}
+
+// bodyCoords converts a position in the body rectangle to the distance from its front end and the
+// distance from its left side.
+func (u *Unit) bodyCoords(x, y float32) (along, across float32) {
+	switch u.Direction {
+	case DirectionRight:
+		return u.bodySize.X - x, y
+	case DirectionLeft:
+		return x, u.bodySize.Y - y
+	case DirectionUp:
+		return y, x
+	default: // DirectionDown
+		return u.bodySize.Y - y, u.bodySize.X - x
+	}
+}
+
+// bodyLength returns the length of the body rectangle along the unit's direction.
+func (u *Unit) bodyLength() float32 {
+	if u.Direction.IsVertical() {
+		return u.bodySize.Y
+	}
+	return u.bodySize.X
+}
+
+// setVertexPart writes the part and its attributes to the color of the vertex.
+func setVertexPart(vertex *ebiten.Vertex, part, attrib1, attrib2, attrib3 float32) {
+	vertex.ColorR = part
+	vertex.ColorG = attrib1
+	vertex.ColorB = attrib2
+	vertex.ColorA = attrib3
+}
+
+func setColorVec4(dst *[4]float32, clr color.RGBA) {
+	*dst = [4]float32{float32(clr.R) / 255, float32(clr.G) / 255, float32(clr.B) / 255, float32(clr.A) / 255}
+}
+
+func boolFloat(b bool) float32 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -28,14 +28,19 @@ import (
 	c "github.com/anilkonac/snake-ebiten/game/core"
 	"github.com/anilkonac/snake-ebiten/game/param"
 	"github.com/anilkonac/snake-ebiten/game/shader"
+	"github.com/anilkonac/snake-ebiten/game/skin"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 var (
 	imageCircle    = ebiten.NewImage(param.SnakeWidth, param.SnakeWidth)
 	shaderMouth    = shader.New(shader.PathCircleMouth)
+	shaderBasic    = shader.New(shader.PathBasic)
 	MouthEnabled   = false
+	RoundedTurns   = true // Draw the turns of skinned snakes as arcs instead of overlapping rectangles
 	optTriangEmpty ebiten.DrawTrianglesOptions
+
+	optTriangShaderEmpty ebiten.DrawTrianglesShaderOptions
 )
 
 func init() {
@@ -50,6 +55,17 @@ func init() {
 
 }
 
+// Sub-stepping parameters
+const sweepStepMax = param.RadiusSnake / 4.0 // Longest sub-step of the head's movement after a swept hit
+
+// Obstacles are tested against the head's movement every tick.
+type Obstacles interface {
+	// Swept returns true if the capsule swept by the head in this tick touches an obstacle.
+	Swept(sweep c.Capsule) bool
+	// Hit returns the contact of the head with an obstacle at its current position.
+	Hit() (c.Contact, bool)
+}
+
 type Snake struct {
 	Speed           float64
 	UnitHead        *Unit
@@ -60,22 +76,39 @@ type Snake struct {
 	growthRemaining float64
 	growthTarget    float64
 	FoodEaten       uint8
+	FixedSpeed      bool // Grow does not change the speed
+	TurnPolicy      *TurnPolicy
+	time            float64 // Seconds elapsed in the snake's updates
 	color           *color.RGBA
 	drawOptsHead    ebiten.DrawTrianglesShaderOptions
+	Skin            *skin.Skin
+	traveled        float64 // Distance traveled by the head, the body pattern is fixed to it
+	proxToFood      float32
+	drawOptsSkin    ebiten.DrawTrianglesShaderOptions
+	vertices        []ebiten.Vertex             // Batch of the skin vertices
+	indices         []uint16                    // Batch of the skin indices
+	skinColors      *[skinColorTotal][4]float32 // Shared with the uniforms, so copies of the snake stay in sync
+	Obstacles       Obstacles                   // Obstacles the head's movement is swept against, if not nil
+	contact         c.Contact
+	hit             bool
+	headMove        float64 // Distance the head has moved in the last tick
+	tailMove        float64 // Distance the tail has moved in the last tick
+	tailDeleted     *Unit   // Tail unit deleted in the last tick, it is linked back while interpolating
+	movedTick       uint64  // Tick of the last update
 }
 
 func NewSnake(headCenter c.Vec64, initialLength uint16, speed float64, direction DirectionT, color *color.RGBA) *Snake {
 	if direction >= DirectionTotal {
 		panic("direction parameter is invalid.")
 	}
-	if headCenter.X > param.ScreenWidth {
+	if headCenter.X > float64(param.WorldWidth) {
 		panic("Initial x position of the snake is off-screen.")
 	}
-	if headCenter.Y > param.ScreenHeight {
+	if headCenter.Y > float64(param.WorldHeight) {
 		panic("Initial y position of the snake is off-screen.")
 	}
-	if isVertical := direction.IsVertical(); (isVertical && (initialLength > param.ScreenHeight)) ||
-		(!isVertical && (initialLength > param.ScreenWidth)) {
+	if isVertical := direction.IsVertical(); (isVertical && (int(initialLength) > param.WorldHeight)) ||
+		(!isVertical && (int(initialLength) > param.WorldWidth)) {
 		panic("Initial snake intersects itself.")
 	}
 	if color == nil {
@@ -85,17 +118,27 @@ func NewSnake(headCenter c.Vec64, initialLength uint16, speed float64, direction
 	initialUnit := NewUnit(headCenter, float64(initialLength), direction, color)
 
 	snake := &Snake{
-		Speed:    speed,
-		UnitHead: initialUnit,
-		unitTail: initialUnit,
-		color:    color,
+		Speed:      speed,
+		UnitHead:   initialUnit,
+		unitTail:   initialUnit,
+		TurnPolicy: &DefaultTurnPolicy,
+		color:      color,
 		drawOptsHead: ebiten.DrawTrianglesShaderOptions{
 			Uniforms: map[string]interface{}{
 				"Radius":      float32(param.RadiusSnake),
 				"RadiusMouth": float32(param.RadiusMouth),
 			},
 		},
+		Skin:       skin.Default(),
+		skinColors: new([skinColorTotal][4]float32),
+		drawOptsSkin: ebiten.DrawTrianglesShaderOptions{
+			Uniforms: map[string]interface{}{
+				"Radius":      float32(param.RadiusSnake),
+				"RadiusMouth": float32(param.RadiusMouth),
+			},
+		},
 	}
+	snake.initSkinUniforms()
 
 	return snake
 }
@@ -107,14 +150,16 @@ func NewSnakeRandDir(headCenter c.Vec64, initialLength uint16, speed float64, co
 
 func NewSnakeRandDirLoc(initialLength uint16, speed float64, color *color.RGBA) *Snake {
 	headCenter := c.Vec64{
-		X: float64(rand.Intn(param.ScreenWidth)),
-		Y: float64(rand.Intn(param.ScreenHeight)),
+		X: float64(rand.Intn(param.WorldWidth)),
+		Y: float64(rand.Intn(param.WorldHeight)),
 	}
 	return NewSnakeRandDir(headCenter, initialLength, speed, color)
 }
 
 func (s *Snake) Update(distToFood float32) {
 	moveDistance := s.Speed * param.DeltaTime
+	s.time += param.DeltaTime
+	s.dropStaleTurns()
 
 	// if the snake has moved a safe distance after the last turn, take the next turn in the queue.
 	if (len(s.turnQueue) > 0) && (s.distAfterTurn+param.ToleranceDefault >= param.SnakeWidth) {
@@ -123,15 +168,63 @@ func (s *Snake) Update(distToFood float32) {
 		s.TurnTo(nextTurn, true)
 	}
 
-	s.updateHead(moveDistance, distToFood)
-	s.updateTail(moveDistance, distToFood)
+	// Sum up the movements if the snake is updated more than once in a tick.
+	if s.movedTick != param.SimTicks {
+		s.headMove, s.tailMove, s.tailDeleted = 0, 0, nil
+	}
+	s.movedTick = param.SimTicks
+
+	headMove := s.updateHead(moveDistance, distToFood)
+	s.headMove += headMove
+	s.tailMove += s.updateTail(headMove, distToFood)
 }
 
-func (s *Snake) updateHead(dist float64, distToFood float32) {
+// updateHead moves the head and returns the distance it has moved. If there are obstacles, the movement
+// is swept, so a fast head cannot tunnel through them. The swept capsule is tested first, and only if it
+// touches an obstacle, the movement is split into sub-steps to stop the head at the first contact.
+func (s *Snake) updateHead(dist float64, distToFood float32) float64 {
+	if (s.Obstacles == nil) || s.hit {
+		s.moveHead(dist)
+	} else if sweep := (c.Capsule{
+		Start:     s.UnitHead.HeadCenter,
+		Direction: directionVectors[s.UnitHead.Direction].To64(),
+		Length:    dist,
+		Radius:    param.RadiusSnake,
+	}); !s.Obstacles.Swept(sweep) {
+		s.moveHead(dist)
+	} else {
+		numSteps := math.Ceil(dist / sweepStepMax)
+		step := dist / numSteps
+		dist = 0
+		for iStep := 0; iStep < int(numSteps); iStep++ {
+			s.moveHead(step)
+			dist += step
+			if s.contact, s.hit = s.Obstacles.Hit(); s.hit {
+				break
+			}
+		}
+	}
+
+	if s.UnitHead != s.unitTail { // Avoid unnecessary updates
+		s.UnitHead.update(distToFood)
+	}
+
+	// Distance to food
+
+	// Update draw options
+	s.proxToFood = 1.0 - distToFood/param.MouthAnimStartDistance
+	s.drawOptsHead.Uniforms["Direction"] = float32(s.UnitHead.Direction)
+	s.drawOptsHead.Uniforms["ProxToFood"] = s.proxToFood
+
+	s.distAfterTurn += dist
+	return dist
+}
+
+func (s *Snake) moveHead(dist float64) {
 	// Increse head length
 	s.UnitHead.length += dist
+	s.traveled += dist
 
-	// Move head
 	switch s.UnitHead.Direction {
 	case DirectionRight:
 		s.UnitHead.moveRight(dist)
@@ -142,22 +235,10 @@ func (s *Snake) updateHead(dist float64, distToFood float32) {
 	case DirectionDown:
 		s.UnitHead.moveDown(dist)
 	}
-
-	if s.UnitHead != s.unitTail { // Avoid unnecessary updates
-		s.UnitHead.update(distToFood)
-	}
-
-	// Distance to food
-
-	// Update draw options
-	proxToFood := 1.0 - distToFood/param.MouthAnimStartDistance
-	s.drawOptsHead.Uniforms["Direction"] = float32(s.UnitHead.Direction)
-	s.drawOptsHead.Uniforms["ProxToFood"] = proxToFood
-
-	s.distAfterTurn += dist
 }
 
-func (s *Snake) updateTail(dist float64, distToFood float32) {
+// updateTail shortens the tail as the head moves and returns the distance the tail has moved.
+func (s *Snake) updateTail(dist float64, distToFood float32) float64 {
 	decreaseAmount := dist
 	if s.growthRemaining > 0 {
 		// Calculate the tail reduction with the square function so that the growth doesn't look ugly.
@@ -174,12 +255,14 @@ func (s *Snake) updateTail(dist float64, distToFood float32) {
 
 	// Delete tail if its length is less than width of the snake
 	if (s.unitTail.prev != nil) && (s.unitTail.length <= param.SnakeWidth) {
+		s.tailDeleted = s.unitTail
 		s.unitTail.prev.length += s.unitTail.length
 		s.unitTail = s.unitTail.prev
 		s.unitTail.Next = nil
 	}
 
 	s.unitTail.update(distToFood)
+	return decreaseAmount
 }
 
 func (s *Snake) TurnTo(newTurn *Turn, isFromQueue bool) {
@@ -188,13 +271,17 @@ func (s *Snake) TurnTo(newTurn *Turn, isFromQueue bool) {
 		if (s.turnPrev != nil) &&
 			(s.turnPrev.isTurningLeft == newTurn.isTurningLeft) &&
 			(s.distAfterTurn+param.ToleranceDefault <= param.SnakeWidth) {
+			// Ignore the turn if it is pressed too early to be queued.
+			if param.SnakeWidth-(s.distAfterTurn+param.ToleranceDefault) > s.TurnPolicy.PreTurnWindow {
+				return
+			}
 			// New turn cannot be taken now, push it into the queue
-			s.turnQueue = append(s.turnQueue, newTurn)
+			s.queueTurn(newTurn)
 			return
 		}
 		// If there are turns in the queue then add the new turn to the queue as well.
 		if len(s.turnQueue) > 0 {
-			s.turnQueue = append(s.turnQueue, newTurn)
+			s.queueTurn(newTurn)
 			return
 		}
 	}
@@ -220,6 +307,74 @@ func (s *Snake) TurnTo(newTurn *Turn, isFromQueue bool) {
 	s.turnPrev = newTurn
 }
 
+// TurnBack reverses the snake with two successive turns. The first turn is taken to the opposite side of the
+// previous turn so that it can be taken immediately.
+func (s *Snake) TurnBack() {
+	dirCurrent := s.LastDirection()
+	turningLeft := true
+	if queueLength := len(s.turnQueue); queueLength > 0 {
+		turningLeft = !s.turnQueue[queueLength-1].isTurningLeft
+	} else if s.turnPrev != nil {
+		turningLeft = !s.turnPrev.isTurningLeft
+	}
+
+	var dirSide DirectionT
+	switch dirCurrent {
+	case DirectionUp:
+		dirSide = DirectionRight
+	case DirectionDown:
+		dirSide = DirectionLeft
+	case DirectionLeft:
+		dirSide = DirectionUp
+	case DirectionRight:
+		dirSide = DirectionDown
+	}
+	if turningLeft {
+		dirSide = dirSide.Opposite()
+	}
+
+	s.TurnTo(NewTurn(dirCurrent, dirSide), false)
+	if s.LastDirection() != dirSide { // First turn was dropped by the turn policy
+		return
+	}
+	s.TurnTo(NewTurn(dirSide, dirCurrent.Opposite()), false)
+}
+
+// queueTurn pushes the turn into the turn queue if the queue is not full.
+func (s *Snake) queueTurn(newTurn *Turn) {
+	if (s.TurnPolicy.MaxQueueDepth > 0) && (len(s.turnQueue) >= s.TurnPolicy.MaxQueueDepth) {
+		return
+	}
+	newTurn.timeQueued = s.time
+	s.turnQueue = append(s.turnQueue, newTurn)
+}
+
+// dropStaleTurns clears the turn queue if its oldest turn has been waiting longer than the policy allows.
+// The whole queue is dropped because every queued turn starts from the direction of the previous one.
+func (s *Snake) dropStaleTurns() {
+	if (s.TurnPolicy.StaleAfterMs <= 0) || (len(s.turnQueue) == 0) {
+		return
+	}
+
+	if (s.time-s.turnQueue[0].timeQueued)*1000 > float64(s.TurnPolicy.StaleAfterMs) {
+		s.turnQueue = s.turnQueue[:0]
+	}
+}
+
+// TurnQueue returns the turns waiting to be taken. The returned slice must not be modified.
+func (s *Snake) TurnQueue() []*Turn {
+	return s.turnQueue
+}
+
+// TurnAge returns how many seconds the queued turn has been waiting.
+func (s *Snake) TurnAge(turn *Turn) float64 {
+	return s.time - turn.timeQueued
+}
+
+func (s *Snake) DistAfterTurn() float64 {
+	return s.distAfterTurn
+}
+
 func (s *Snake) Grow() {
 	// Compute the new growth and add to the remaining growth value.
 	// f(x)=50+5*log2(x/10.0+1)
@@ -230,9 +385,113 @@ func (s *Snake) Grow() {
 
 	// Update snake speed
 	// f(x)=250+25/e^(0.0075x)
+	if s.FixedSpeed {
+		return
+	}
 	s.Speed = param.SnakeSpeedFinal + (param.SnakeSpeedInitial-param.SnakeSpeedFinal)/math.Exp(0.0075*float64(s.FoodEaten))
 }
 
+// Length returns the total length of the snake's units.
+func (s *Snake) Length() float64 {
+	var length float64
+	for unit := s.UnitHead; unit != nil; unit = unit.Next {
+		length += unit.length
+	}
+	return length
+}
+
+// Teleport moves the whole snake so that its head center is at the position.
+func (s *Snake) Teleport(headCenter c.Vec64) {
+	offset := c.Vec64{X: headCenter.X - s.UnitHead.HeadCenter.X, Y: headCenter.Y - s.UnitHead.HeadCenter.Y}
+	for unit := s.UnitHead; unit != nil; unit = unit.Next {
+		unit.HeadCenter.X = wrapCoord(unit.HeadCenter.X+offset.X, float64(param.WorldWidth))
+		unit.HeadCenter.Y = wrapCoord(unit.HeadCenter.Y+offset.Y, float64(param.WorldHeight))
+		unit.update(param.MouthAnimStartDistance)
+	}
+	s.movedTick = 0 // Do not interpolate the jump
+}
+
+func wrapCoord(value, size float64) float64 {
+	value = math.Mod(value, size)
+	if value < 0 {
+		value += size
+	}
+	return value
+}
+
+// HeadCircle returns the shape of the head with the radius.
+func (s *Snake) HeadCircle(radius float64) c.Circle {
+	return c.Circle{Center: s.UnitHead.HeadCenter, Radius: radius}
+}
+
+// HitsItself returns the contact of the head with the unit if they overlap more than ToleranceDefault,
+// as the turn policy lets U-turns start that much early.
+func (s *Snake) HitsItself(unit *Unit) (c.Contact, bool) {
+	capsule, ok := s.BodyCapsule(unit)
+	if !ok {
+		return c.Contact{}, false
+	}
+
+	contact, overlaps := s.HeadCircle(param.RadiusSnake).OverlapsCapsule(capsule)
+	if !overlaps || (contact.Penetration <= param.ToleranceDefault) {
+		return c.Contact{}, false
+	}
+	return contact, true
+}
+
+// BodyCapsule returns the capsule of the unit that the head can hit. The body near the head is skipped,
+// since it touches the head around the turns. A real hit needs a U-turn at least, so the skipped length
+// is the diagonal of the head's square. It returns false if the whole unit is skipped or if the unit is
+// not one of the snake's units.
+func (s *Snake) BodyCapsule(unit *Unit) (c.Capsule, bool) {
+	if unit == s.UnitHead {
+		return c.Capsule{}, false
+	}
+
+	var distFromHead float64 // Distance along the body from the head center to the unit's head center
+	for own := s.UnitHead; own != unit; own = own.Next {
+		if own == nil {
+			return c.Capsule{}, false
+		}
+		distFromHead += own.length
+	}
+
+	capsule := unit.Capsule()
+	if skip := math.Sqrt2*param.SnakeWidth - distFromHead; skip > 0 {
+		if skip >= capsule.Length {
+			return c.Capsule{}, false
+		}
+		capsule.Start.X += capsule.Direction.X * skip
+		capsule.Start.Y += capsule.Direction.Y * skip
+		capsule.Length -= skip
+	}
+	return capsule, true
+}
+
+// Hit returns the contact of the head with an obstacle found while its movement was swept.
+func (s *Snake) Hit() (c.Contact, bool) {
+	return s.contact, s.hit
+}
+
+// Owns returns true if the unit is one of the snake's units.
+func (s *Snake) Owns(unit *Unit) bool {
+	for own := s.UnitHead; own != nil; own = own.Next {
+		if own == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// TailCenter returns the center of the snake's tail end.
+func (s *Snake) TailCenter() c.Vec64 {
+	return s.unitTail.BackCenter()
+}
+
+func (s *Snake) Color() *color.RGBA {
+	return s.color
+}
+
 func (s *Snake) LastDirection() DirectionT {
 	// if the turn queue is not empty, return the direction of the last turn to be taken.
 	if queueLength := len(s.turnQueue); queueLength > 0 {
@@ -243,7 +502,86 @@ func (s *Snake) LastDirection() DirectionT {
 	return s.UnitHead.Direction
 }
 
+// Draw draws the snake between its last two simulation states, so its movement stays smooth when the
+// frame rate differs from the simulation rate.
 func (s *Snake) Draw(dst *ebiten.Image) {
+	if (s.movedTick == param.StepTick) && (param.RenderAlpha < 1) {
+		s.drawInterpolated(dst, 1-param.RenderAlpha)
+		return
+	}
+	s.draw(dst)
+}
+
+// drawInterpolated moves the head and the tail back by the part of the last update that has not been
+// reached yet, draws the snake and restores them. The head cannot go back beyond its last turn. If the
+// tail unit has been deleted in the last update and the tail goes back beyond the turn, the deleted unit
+// is linked back, so the tail keeps moving along it instead of jumping to the turn.
+func (s *Snake) drawInterpolated(dst *ebiten.Image, lag float64) {
+	head, tail := s.UnitHead, s.unitTail
+	headCenter, headLength, tailLength, traveled := head.HeadCenter, head.length, tail.length, s.traveled
+
+	tailLag := s.tailMove * lag
+	deleted := s.tailDeleted
+	relinked := (deleted != nil) && (deleted.length+tailLag > param.SnakeWidth)
+	var deletedLength float64
+	if relinked {
+		deletedLength = deleted.length
+		tail.length -= deletedLength
+		tail.Next, s.unitTail = deleted, deleted
+		deleted.length += tailLag
+		tail.update(0)
+	} else {
+		tail.length += tailLag
+	}
+
+	headLag := math.Min(s.headMove*lag, head.length)
+	s.moveHeadBack(headLag)
+	s.updateEnds()
+	s.draw(dst)
+
+	if relinked {
+		deleted.length = deletedLength
+		tail.Next, s.unitTail = nil, tail
+	}
+	head.HeadCenter, head.length, tail.length, s.traveled = headCenter, headLength, tailLength, traveled
+	s.updateEnds()
+}
+
+// moveHeadBack undoes a movement of the head by moving it in the opposite direction.
+func (s *Snake) moveHeadBack(dist float64) {
+	s.UnitHead.length -= dist
+	s.traveled -= dist
+
+	switch s.UnitHead.Direction {
+	case DirectionRight:
+		s.UnitHead.moveLeft(dist)
+	case DirectionLeft:
+		s.UnitHead.moveRight(dist)
+	case DirectionUp:
+		s.UnitHead.moveDown(dist)
+	case DirectionDown:
+		s.UnitHead.moveUp(dist)
+	}
+}
+
+// updateEnds updates the components of the head and tail units after their positions are changed.
+func (s *Snake) updateEnds() {
+	s.UnitHead.update(0)
+	if s.unitTail != s.UnitHead {
+		s.unitTail.update(0)
+	}
+}
+
+func (s *Snake) draw(dst *ebiten.Image) {
+	if param.DebugUnits || (s.Skin == nil) {
+		s.drawUnits(dst)
+		return
+	}
+	s.drawSkin(dst)
+}
+
+// drawUnits draws the units with their own flat colors.
+func (s *Snake) drawUnits(dst *ebiten.Image) {
 	for unit := s.UnitHead; unit != nil; unit = unit.Next {
 		// Draw circle centered on unit's head center
 		vertices, indices := unit.CompTriangHead.Triangles()
@@ -260,7 +598,8 @@ func (s *Snake) Draw(dst *ebiten.Image) {
 		}
 
 		// Draw rectangle starts from unit's head center to the tail head center
-		unit.CompBody.Draw(dst)
+		vertices, indices = unit.CompBody.Triangles()
+		dst.DrawTrianglesShader(vertices, indices, shaderBasic, &optTriangShaderEmpty)
 
 		if param.DebugUnits {
 			unit.DrawDebugInfo(dst)
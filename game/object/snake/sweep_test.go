@@ -0,0 +1,182 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package snake
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+// Sweep test parameters
+const (
+	sweepLaneY     = 300.0 // The fired snake moves right on this line
+	sweepStartX    = 100.3 // Not aligned with the tick movements, so the hit tick is not at a boundary
+	sweepWallX     = 700.0
+	sweepWrapX     = 930.3 // Start of the snake that crosses the right edge before the wall
+	sweepWrapWallX = 40.0
+	sweepTurnX     = 500.0 // The turning segment of the other snake is vertical at this x
+	sweepTicksMax  = 10000
+)
+
+// capsuleObstacles tests the head of the snake against fixed capsules.
+type capsuleObstacles struct {
+	snake    *Snake
+	capsules []c.Capsule
+}
+
+func (o *capsuleObstacles) Swept(sweep c.Capsule) bool {
+	for _, capsule := range o.capsules {
+		if sweep.SegmentDistance(capsule) < sweep.Radius+capsule.Radius {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *capsuleObstacles) Hit() (c.Contact, bool) {
+	for _, capsule := range o.capsules {
+		if contact, hit := o.snake.HeadCircle(param.RadiusSnake).OverlapsCapsule(capsule); hit {
+			return contact, true
+		}
+	}
+	return c.Contact{}, false
+}
+
+// wall returns a vertical wall of the thickness across the lane.
+func wall(x, thickness float64) c.Capsule {
+	return c.Capsule{
+		Start:     c.Vec64{X: x, Y: sweepLaneY - 100},
+		Direction: directionVectors[DirectionDown].To64(),
+		Length:    200,
+		Radius:    thickness / 2.0,
+	}
+}
+
+// turningSegment returns the capsules of a snake that has moved right and then turned down, so its
+// vertical segment crosses the lane at sweepTurnX.
+func turningSegment(t *testing.T) []c.Capsule {
+	t.Helper()
+	other := NewSnake(c.Vec64{X: sweepTurnX, Y: sweepLaneY - 100}, 400, 150, DirectionRight, &param.ColorSnake2)
+	other.TurnTo(NewTurn(DirectionRight, DirectionDown), false)
+
+	deltaTime := param.DeltaTime
+	param.DeltaTime = 1 // Move the whole vertical segment in one update
+	other.Update(param.MouthAnimStartDistance)
+	param.DeltaTime = deltaTime
+
+	var capsules []c.Capsule
+	for unit := other.UnitHead; unit != nil; unit = unit.Next {
+		capsules = append(capsules, unit.Capsule())
+	}
+	if len(capsules) != 2 {
+		t.Fatalf("the other snake has %d units, want 2", len(capsules))
+	}
+	return capsules
+}
+
+// setSimRate sets the simulation rate and the world of a test and restores them after it.
+func setSimRate(t *testing.T, rate int) {
+	t.Helper()
+	simRatePrev, deltaTimePrev := param.SimRate, param.DeltaTime
+	widthPrev, heightPrev, teleportPrev := param.WorldWidth, param.WorldHeight, param.TeleportEnabled
+	param.SimRate, param.DeltaTime = rate, 1.0/float64(rate)
+	param.WorldWidth, param.WorldHeight, param.TeleportEnabled = param.ScreenWidthDefault, param.ScreenHeightDefault, true
+	t.Cleanup(func() {
+		param.SimRate, param.DeltaTime = simRatePrev, deltaTimePrev
+		param.WorldWidth, param.WorldHeight, param.TeleportEnabled = widthPrev, heightPrev, teleportPrev
+	})
+}
+
+// TestSweptHeadStopsAtThinObstacles fires snakes at thin walls and at a turning segment of another snake
+// with speeds that move the head further than the obstacles' thickness in a tick. The head must stop at
+// the first sub-step touching the obstacle: the hit tick is the first one whose movement reaches the
+// obstacle, and the contact point is at most half of a sub-step into it.
+func TestSweptHeadStopsAtThinObstacles(t *testing.T) {
+	type obstacle struct {
+		name     string
+		startX   float64
+		surfaceX float64 // Unwrapped x of the obstacle's left surface
+		capsules func(t *testing.T) []c.Capsule
+	}
+	obstacles := []obstacle{
+		{"1px wall", sweepStartX, sweepWallX - 0.5, func(*testing.T) []c.Capsule {
+			return []c.Capsule{wall(sweepWallX, 1)}
+		}},
+		{"2px wall", sweepStartX, sweepWallX - 1, func(*testing.T) []c.Capsule {
+			return []c.Capsule{wall(sweepWallX, 2)}
+		}},
+		{"turning segment", sweepStartX, sweepTurnX - param.RadiusSnake, turningSegment},
+		{"2px wall across the wrap edge", sweepWrapX, float64(param.ScreenWidthDefault) + sweepWrapWallX - 1, func(*testing.T) []c.Capsule {
+			return []c.Capsule{wall(sweepWrapWallX, 2)}
+		}},
+	}
+	simRates := []int{60, 120, 240}
+	speeds := []float64{400, 1200, 4000} // Normal top speed, boosted and extreme
+
+	for _, simRate := range simRates {
+		for _, speed := range speeds {
+			for _, obs := range obstacles {
+				obs := obs
+				name := fmt.Sprintf("%dHz/%.0fpx_s/%s", simRate, speed, obs.name)
+				t.Run(name, func(t *testing.T) {
+					setSimRate(t, simRate)
+					snake := NewSnake(c.Vec64{X: obs.startX, Y: sweepLaneY}, 100, speed, DirectionRight, &param.ColorSnake1)
+					snake.Obstacles = &capsuleObstacles{snake: snake, capsules: obs.capsules(t)}
+
+					// The head touches the obstacle after it has moved this far.
+					reach := obs.surfaceX - param.RadiusSnake - obs.startX
+					move := speed * param.DeltaTime
+					wantTick := int(math.Floor(reach/move)) + 1
+
+					var tick int
+					var contact c.Contact
+					for hit := false; !hit; {
+						if tick++; tick > sweepTicksMax {
+							t.Fatal("the head has not hit the obstacle")
+						}
+						snake.Update(param.MouthAnimStartDistance)
+						contact, hit = snake.Hit()
+					}
+
+					if tick != wantTick {
+						t.Errorf("hit at tick %d, want %d", tick, wantTick)
+					}
+
+					// The head stops at the first sub-step with a contact, so it cannot be deeper than a sub-step.
+					if contact.Penetration <= 0 || contact.Penetration > sweepStepMax+1e-9 {
+						t.Errorf("penetration = %v, want in (0, %v]", contact.Penetration, sweepStepMax)
+					}
+					surfaceX := c.Wrap(float32(obs.surfaceX), float32(param.WorldWidth))
+					wantMin, wantMax := float64(surfaceX), float64(surfaceX)+sweepStepMax/2.0
+					if (contact.Point.X <= wantMin-1e-3) || (contact.Point.X > wantMax+1e-3) {
+						t.Errorf("contact point x = %v, want in (%v, %v]", contact.Point.X, wantMin, wantMax)
+					}
+					if math.Abs(contact.Point.Y-sweepLaneY) > 1e-9 {
+						t.Errorf("contact point y = %v, want %v", contact.Point.Y, sweepLaneY)
+					}
+				})
+			}
+		}
+	}
+}
@@ -29,6 +29,8 @@ const (
 	DirectionTotal
 )
 
+var directionNames = [DirectionTotal]string{"Up", "Down", "Left", "Right"}
+
 func (d DirectionT) IsVertical() bool {
 	if d >= DirectionTotal {
 		panic("wrong direction")
@@ -36,17 +38,52 @@ func (d DirectionT) IsVertical() bool {
 	return (d == DirectionUp) || (d == DirectionDown)
 }
 
+// Opposite returns the reverse of the direction.
+func (d DirectionT) Opposite() DirectionT {
+	switch d {
+	case DirectionUp:
+		return DirectionDown
+	case DirectionDown:
+		return DirectionUp
+	case DirectionLeft:
+		return DirectionRight
+	case DirectionRight:
+		return DirectionLeft
+	}
+	panic("wrong direction")
+}
+
+func (d DirectionT) String() string {
+	if d >= DirectionTotal {
+		return "Invalid"
+	}
+	return directionNames[d]
+}
+
 type Turn struct {
 	directionTo   DirectionT
 	isTurningLeft bool
+	timeQueued    float64 // Snake time in seconds when the turn is pushed into the queue
 }
 
 func NewTurn(directionFrom, directionTo DirectionT) *Turn {
 	return &Turn{
-		directionTo: directionTo,
-		isTurningLeft: (directionFrom == DirectionUp && directionTo == DirectionLeft) ||
-			(directionFrom == DirectionLeft && directionTo == DirectionDown) ||
-			(directionFrom == DirectionDown && directionTo == DirectionRight) ||
-			(directionFrom == DirectionRight && directionTo == DirectionUp),
+		directionTo:   directionTo,
+		isTurningLeft: isTurnLeft(directionFrom, directionTo),
 	}
 }
+
+func isTurnLeft(directionFrom, directionTo DirectionT) bool {
+	return (directionFrom == DirectionUp && directionTo == DirectionLeft) ||
+		(directionFrom == DirectionLeft && directionTo == DirectionDown) ||
+		(directionFrom == DirectionDown && directionTo == DirectionRight) ||
+		(directionFrom == DirectionRight && directionTo == DirectionUp)
+}
+
+func (t *Turn) DirectionTo() DirectionT {
+	return t.directionTo
+}
+
+func (t *Turn) IsTurningLeft() bool {
+	return t.isTurningLeft
+}
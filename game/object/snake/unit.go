@@ -33,12 +33,13 @@ type Unit struct {
 	length          float64
 	Direction       DirectionT
 	CompCollision   c.TeleComp
-	CompBody        c.TeleCompImage
+	CompBody        c.TeleCompTriang
 	CompTriangDebug c.TeleCompTriang
 	CompTriangHead  c.TeleCompTriang
 	CompTriangTail  c.TeleCompTriang
 	Next            *Unit
 	prev            *Unit
+	bodySize        c.Vec32 // Size of the body rectangle before it is split
 }
 
 func NewUnit(headCenter c.Vec64, length float64, direction DirectionT, color *color.RGBA) *Unit {
@@ -192,6 +193,7 @@ func (u *Unit) update(distToFood float32) {
 	u.CompTriangDebug.Update(rectDraw)
 	u.CompTriangHead.Update(rectDrawHead)
 	u.CompBody.Update(rectDrawBody)
+	u.bodySize = rectDrawBody.Size
 
 	// If current unit is the tail unit
 	if u.Next == nil {
@@ -205,7 +207,7 @@ func (u *Unit) moveUp(dist float64) {
 
 	// teleport if head center is offscreen.
 	if param.TeleportEnabled && (u.HeadCenter.Y < 0) {
-		u.HeadCenter.Y += param.ScreenHeight
+		u.HeadCenter.Y += float64(param.WorldHeight)
 	}
 }
 
@@ -213,8 +215,8 @@ func (u *Unit) moveDown(dist float64) {
 	u.HeadCenter.Y += dist
 
 	// teleport if head center is offscreen.
-	if param.TeleportEnabled && (u.HeadCenter.Y > param.ScreenHeight) {
-		u.HeadCenter.Y -= param.ScreenHeight
+	if param.TeleportEnabled && (u.HeadCenter.Y > float64(param.WorldHeight)) {
+		u.HeadCenter.Y -= float64(param.WorldHeight)
 	}
 }
 
@@ -222,8 +224,8 @@ func (u *Unit) moveRight(dist float64) {
 	u.HeadCenter.X += dist
 
 	// teleport if head center is offscreen.
-	if param.TeleportEnabled && (u.HeadCenter.X > param.ScreenWidth) {
-		u.HeadCenter.X -= param.ScreenWidth
+	if param.TeleportEnabled && (u.HeadCenter.X > float64(param.WorldWidth)) {
+		u.HeadCenter.X -= float64(param.WorldWidth)
 	}
 }
 
@@ -232,13 +234,18 @@ func (u *Unit) moveLeft(dist float64) {
 
 	// teleport if head center is offscreen.
 	if param.TeleportEnabled && (u.HeadCenter.X < 0) {
-		u.HeadCenter.X += param.ScreenWidth
+		u.HeadCenter.X += float64(param.WorldWidth)
 	}
 }
 
 func (u *Unit) markHeadCenters(dst *ebiten.Image) {
-	c.MarkPoint(dst, u.HeadCenter, 4, param.ColorFood)
+	c.MarkPoint(dst, c.Cam.ToScreen(u.HeadCenter), 4, param.ColorFood)
+	// mark head center at the other side
+	c.MarkPoint(dst, c.Cam.ToScreen(u.BackCenter()), 4, param.ColorFood)
+}
 
+// BackCenter returns the center at the back end of the unit. It is the tail center for the last unit.
+func (u *Unit) BackCenter() c.Vec64 {
 	var offset float64 = 0
 	if u.Next == nil {
 		offset = param.SnakeWidth
@@ -255,8 +262,26 @@ func (u *Unit) markHeadCenters(dst *ebiten.Image) {
 	case DirectionLeft:
 		backCenter.X = u.HeadCenter.X + u.length - offset
 	}
-	// mark head center at the other side
-	c.MarkPoint(dst, backCenter, 4, param.ColorFood)
+	return backCenter
+}
+
+// Capsule returns the shape of the unit's body from its head center to its back center.
+func (u *Unit) Capsule() c.Capsule {
+	length := u.length
+	if u.Next == nil {
+		length -= param.SnakeWidth
+	}
+	return c.Capsule{
+		Start:     u.HeadCenter,
+		Direction: directionVectors[u.Direction.Opposite()].To64(),
+		Length:    math.Max(length, 0),
+		Radius:    param.RadiusSnake,
+	}
+}
+
+// Length returns the distance from the head center of the unit to the head center of the next one.
+func (u *Unit) Length() float64 {
+	return u.length
 }
 
 func (u *Unit) SetColor(clr *color.RGBA) {
@@ -273,12 +298,12 @@ func (u *Unit) DrawDebugInfo(dst *ebiten.Image) {
 	}
 }
 
-// Implement collidable interface
+// Implement Collidable interface
 // ------------------------------
 func (u *Unit) CollEnabled() bool {
 	return true
 }
 
 func (u *Unit) CollisionRects() []c.RectF32 {
-	return u.CompCollision.Rects[:]
+	return u.CompCollision.Rects[:u.CompCollision.NumRects]
 }
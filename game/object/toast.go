@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package object
+
+import (
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// Toast parameters
+const (
+	toastPaddingX  = 16
+	toastPaddingY  = 10
+	toastLineSpace = 8
+	toastSpacingY  = 10  // Space between the stacked toasts
+	toastSlideTime = 0.3 // seconds
+	toastShowTime  = 3.0 // seconds
+	toastFadeTime  = 1.0 // seconds
+	toastRiseSpeed = 25  // Speed while fading out, same as the score animation
+)
+
+// Toast is a notification that slides in at the top of the screen, stays for a while and then rises and
+// fades out like the score animation.
+type Toast struct {
+	image    *ebiten.Image
+	time     float32
+	drawOpts ebiten.DrawImageOptions
+}
+
+func NewToast(title, msg string, faceTitle, faceMsg font.Face) *Toast {
+	boundTitle := text.BoundString(faceTitle, title)
+	boundMsg := text.BoundString(faceMsg, msg)
+	width := boundTitle.Dx()
+	if boundMsg.Dx() > width {
+		width = boundMsg.Dx()
+	}
+
+	// Prepare the image of the toast in the colors of the title rectangle
+	toastImage := ebiten.NewImage(width+2*toastPaddingX, boundTitle.Dy()+boundMsg.Dy()+toastLineSpace+2*toastPaddingY)
+	toastImage.Fill(param.ColorSnake2)
+	text.Draw(toastImage, title, faceTitle, toastPaddingX-boundTitle.Min.X, toastPaddingY-boundTitle.Min.Y, param.ColorBackground)
+	text.Draw(toastImage, msg, faceMsg, toastPaddingX-boundMsg.Min.X,
+		toastPaddingY+boundTitle.Dy()+toastLineSpace-boundMsg.Min.Y, param.ColorBackground)
+
+	return &Toast{image: toastImage}
+}
+
+// Update returns true when the animation is finished
+func (t *Toast) Update() bool {
+	t.time += float32(param.DeltaTime)
+	if t.time < toastSlideTime+toastShowTime+toastFadeTime {
+		return false
+	}
+
+	t.image.Dispose()
+	return true
+}
+
+// Draw draws the toast centered at posY from the top of the screen and returns the space it takes,
+// so the next toast can be drawn below it.
+func (t *Toast) Draw(dst *ebiten.Image, posY float32) float32 {
+	size := t.image.Bounds().Size()
+	alpha := float32(1.0)
+
+	switch fadeStart := float32(toastSlideTime + toastShowTime); {
+	case t.time < toastSlideTime:
+		// Slide in from above the screen with ease out
+		progress := t.time / toastSlideTime
+		posY -= (1 - progress*(2-progress)) * (posY + float32(size.Y))
+	case t.time > fadeStart:
+		posY -= toastRiseSpeed * (t.time - fadeStart)
+		alpha = 1 - (t.time-fadeStart)/toastFadeTime
+	}
+
+	t.drawOpts.GeoM.Reset()
+	t.drawOpts.GeoM.Translate(float64(param.ScreenWidth-size.X)/2.0, float64(posY))
+	t.drawOpts.ColorM.Reset()
+	t.drawOpts.ColorM.Scale(1, 1, 1, float64(alpha))
+	dst.DrawImage(t.image, &t.drawOpts)
+
+	return float32(size.Y + toastSpacingY)
+}
@@ -0,0 +1,238 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/sound"
+	"github.com/anilkonac/snake-ebiten/game/theme"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Options scene layout parameters
+const (
+	textOptionsTitle = "Options"
+	textOptionsHelp  = "Up/Down: Select   Left/Right: Change   Esc: Save & Back"
+	optionsValueX    = 480
+	optionsValueW    = 340
+)
+
+// option is a row of the options scene whose value is changed with the left and right keys.
+type option struct {
+	label  string
+	value  func() string
+	change func(delta int) // delta is -1 for left and +1 for right
+}
+
+type optionsScene struct {
+	options     []option
+	selected    int
+	scroll      int // Index of the first visible option
+	worldWidth  int // World size when the scene is opened
+	worldHeight int
+}
+
+func newOptionsScene() *optionsScene {
+	scene := &optionsScene{worldWidth: param.WorldWidth, worldHeight: param.WorldHeight}
+	scene.options = append(scene.options, option{
+		label: "Theme",
+		value: func() string {
+			return theme.Current().Name
+		},
+		change: selectTheme,
+	})
+	scene.options = append(scene.options, option{
+		label:  "Aspect ratio",
+		value:  aspectRatioName,
+		change: selectAspectRatio,
+	})
+	scene.options = append(scene.options, option{
+		label:  "World size",
+		value:  worldScaleName,
+		change: selectWorldScale,
+	})
+	scene.options = append(scene.options, option{
+		label: "Fullscreen",
+		value: func() string {
+			return onOff(ebiten.IsFullscreen())
+		},
+		change: func(int) {
+			toggleFullscreen()
+		},
+	})
+	scene.options = append(scene.options, option{
+		label: "Dust trail",
+		value: func() string {
+			return onOff(display.DustTrail)
+		},
+		change: func(int) {
+			toggleDustTrail()
+		},
+	})
+	scene.options = append(scene.options, option{
+		label: "Rounded turns",
+		value: func() string {
+			return onOff(display.RoundedTurns)
+		},
+		change: func(int) {
+			toggleRoundedTurns()
+		},
+	})
+	scene.options = append(scene.options, option{
+		label:  "Simulation rate",
+		value:  simRateName,
+		change: selectSimRate,
+	})
+	for iEffect := range postEffects {
+		scene.options = append(scene.options, postEffectOption(&postEffects[iEffect]))
+	}
+	for bus := sound.BusMaster; bus < sound.BusTotal; bus++ {
+		scene.options = append(scene.options, volumeOption(bus))
+	}
+	scene.options = append(scene.options, option{
+		label: "Shuffle music",
+		value: func() string {
+			return onOff(sound.Shuffle())
+		},
+		change: func(int) {
+			sound.ToggleShuffle()
+		},
+	})
+	scene.options = append(scene.options, option{
+		label: "Adaptive music",
+		value: func() string {
+			return onOff(sound.Adaptive())
+		},
+		change: func(int) {
+			sound.ToggleAdaptive()
+		},
+	})
+	scene.options = append(scene.options, option{
+		label: "Sound effects",
+		value: func() string {
+			if sound.SynthSFX() {
+				return "Synthesized"
+			}
+			return "Recorded"
+		},
+		change: func(int) {
+			sound.ToggleSynthSFX()
+		},
+	})
+	return scene
+}
+
+func volumeOption(bus sound.Bus) option {
+	return option{
+		label: bus.String() + " volume",
+		value: func() string {
+			return fmt.Sprintf("%3.0f%%", sound.Volume(bus)*100)
+		},
+		change: func(delta int) {
+			sound.StepVolume(bus, delta > 0)
+		},
+	}
+}
+
+func postEffectOption(effect *postEffect) option {
+	return option{
+		label: effect.name,
+		value: func() string {
+			return onOff(*effect.enabled)
+		},
+		change: func(int) {
+			togglePostEffect(effect)
+		},
+	}
+}
+
+func (o *optionsScene) update() bool {
+	switch {
+	case input.IsKeyJustPressed(ebiten.KeyUp) && o.selected > 0:
+		o.selected--
+	case input.IsKeyJustPressed(ebiten.KeyDown) && o.selected < len(o.options)-1:
+		o.selected++
+	case input.IsKeyJustPressed(ebiten.KeyLeft):
+		o.options[o.selected].change(-1)
+	case input.IsKeyJustPressed(ebiten.KeyRight):
+		o.options[o.selected].change(+1)
+	case input.IsKeyJustPressed(ebiten.KeyEscape):
+		o.save()
+		return true
+	}
+
+	return false
+}
+
+func (o *optionsScene) worldChanged() bool {
+	return (o.worldWidth != param.WorldWidth) || (o.worldHeight != param.WorldHeight)
+}
+
+func (o *optionsScene) save() {
+	if err := sound.SaveSettings(); err != nil {
+		fmt.Println("Could not save the audio settings:", err)
+	}
+}
+
+func (o *optionsScene) draw(screen *ebiten.Image) {
+	drawBackground(screen)
+
+	// Draw title
+	boundTitle := text.BoundString(param.FontFaceScore, textOptionsTitle)
+	text.Draw(screen, textOptionsTitle, param.FontFaceScore,
+		(param.ScreenWidth-boundTitle.Size().X)/2-boundTitle.Min.X, controlsTitleShiftY-boundTitle.Min.Y, param.ColorScore)
+
+	// Scroll the options so that the selected one is visible
+	numVisible := (param.ScreenHeight - controlsTableShiftY - controlsRowHeight) / controlsRowHeight
+	if o.selected < o.scroll {
+		o.scroll = o.selected
+	} else if o.selected >= o.scroll+numVisible {
+		o.scroll = o.selected - numVisible + 1
+	}
+
+	// Draw options
+	for iOption := o.scroll; (iOption < len(o.options)) && (iOption < o.scroll+numVisible); iOption++ {
+		opt := o.options[iOption]
+		rowY := controlsTableShiftY + (iOption-o.scroll)*controlsRowHeight
+		text.Draw(screen, opt.label, fontFaceMenu, controlsLabelX, rowY, param.ColorDebug)
+
+		clrValue := param.ColorDebug
+		if iOption == o.selected {
+			ebitenutil.DrawRect(screen, optionsValueX, float64(rowY-controlsRowHeight*3/4),
+				optionsValueW, controlsRowHeight-controlsCellPaddingX, param.ColorSnake2)
+			clrValue = param.ColorBackground
+		}
+		text.Draw(screen, "< "+opt.value()+" >", fontFaceMenu, optionsValueX+controlsCellPaddingX, rowY, clrValue)
+	}
+
+	text.Draw(screen, textOptionsHelp, fontFaceDebug, controlsLabelX, param.ScreenHeight-controlsHelpShiftY, param.ColorDebug)
+}
+
+func onOff(on bool) string {
+	if on {
+		return "On"
+	}
+	return "Off"
+}
@@ -8,22 +8,39 @@ import (
 )
 
 const (
-	ScreenWidth      = 960
-	ScreenHeight     = 720
-	HalfScreenWidth  = ScreenWidth / 2.0
-	HalfScreenHeight = ScreenHeight / 2.0
-	DeltaTime        = 1.0 / 60.0
+	ScreenWidthDefault  = 960
+	ScreenHeightDefault = 720
+	SimRateDefault      = 120 // Simulation steps per second
+)
+
+// The simulation runs with a fixed time step at the sim rate, independent of the display refresh rate.
+// The frames are drawn between the last two simulation states at RenderAlpha. The objects that have moved
+// in the tick of the last step are interpolated, it is an earlier tick if the time is slowed down.
+var (
+	SimRate     = SimRateDefault
+	DeltaTime   = 1.0 / float64(SimRateDefault)
+	SimTicks    uint64  // Ticks taken since the start, each is one step unless the time is scaled
+	StepTick    uint64  // Tick of the last simulation step of the drawn scene
+	RenderAlpha float64 = 1.0
+)
+
+// Size of the logical screen, the window scales it with letterboxing.
+// The world wraps at its own bounds and is scrolled by the camera when it is larger than the screen.
+var (
+	ScreenWidth  = ScreenWidthDefault
+	ScreenHeight = ScreenHeightDefault
+	WorldWidth   = ScreenWidthDefault
+	WorldHeight  = ScreenHeightDefault
 )
 
 // Food parameters
 const (
-	FoodScore    = 100
-	FoodLength   = 16
-	RadiusFood   = FoodLength / 2.0
-	RadiusEating = RadiusMouth + RadiusFood
+	FoodScore  = 100
+	FoodLength = 16
+	RadiusFood = FoodLength / 2.0
 )
 
-// Colors to be used in the drawing.
+// Colors to be used in the drawing. They are overwritten in place by the current theme.
 // Palette: https://coolors.co/palette/003049-d62828-f77f00-fcbf49-eae2b7
 var (
 	ColorBackground = color.RGBA{0, 48, 73, 255}     // ~ Prussian Blue
@@ -32,12 +49,16 @@ var (
 	ColorFood       = color.RGBA{214, 40, 40, 255}   // ~ Maximum Red
 	ColorDebug      = color.RGBA{234, 226, 183, 255} // ~ Lemon Meringue
 	ColorScore      = color.RGBA{247, 127, 0, 255}   // ~ Orange
+	CornerRadius    = float32(RadiusSnake)
+	ThemeVersion    uint32 // Incremented whenever a theme is applied so that the colors can be refreshed.
 )
 
 var (
 	TeleportEnabled = true
 	PrintFPS        = true
 	DebugUnits      = false // Draw consecutive units with different colors
+	DebugTurns      = false // Draw the turn queue of the player's snake
+	TimeScale       = 1.0   // Simulation steps of the game per tick, changed by the debug keys
 	ShaderRound     *ebiten.Shader
 	FontFaceScore   font.Face
 )
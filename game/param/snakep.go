@@ -11,8 +11,7 @@ const (
 	RadiusMouth            = RadiusSnake * 0.625
 )
 
-// Snake collision tolerances must be an integer or false collisions will occur.
+// Snake collision parameters
 const (
-	ToleranceDefault    = 2 //param.SnakeWidth / 16.0
-	ToleranceScreenEdge = RadiusSnake
+	ToleranceDefault = 2 // Allowed overlap of the snake's own parts, U-turns can start this much early.
 )
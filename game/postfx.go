@@ -0,0 +1,211 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/anilkonac/snake-ebiten/game/shader"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Post effect parameters
+const (
+	settingsPostEffects = "postEffects"
+	crtCurvature        = 0.04
+	crtScanlineAlpha    = 0.25
+	bloomRadius         = 12.0 // pixels
+	bloomIntensity      = 0.9
+	chromaticMaxAmount  = 8.0 // pixels
+	chromaticFadeTime   = 1.5 // seconds
+	vignetteStrength    = 0.6
+	pauseBlurRadius     = 6.0  // pixels
+	pauseBlurRate       = 0.15 // Ratio of the distance to the target radius covered in a tick
+	pauseBlurIdleRadius = 0.05 // pixels, the blur is skipped below this radius
+)
+
+// postEffect is a full screen shader pass applied to the frame when it is enabled in the settings.
+// Its uniforms are created once and updated in place before every pass.
+type postEffect struct {
+	name     string
+	shader   *ebiten.Shader
+	enabled  *bool
+	update   func(uniforms map[string]interface{}) // Nil if the uniforms are constant
+	idle     func() bool                           // Reports whether the pass would not change the frame, nil if never
+	drawOpts ebiten.DrawRectShaderOptions
+}
+
+func newPostEffect(name, path string, enabled *bool, uniforms map[string]interface{},
+	update func(uniforms map[string]interface{}), idle func() bool) postEffect {
+	return postEffect{
+		name:     name,
+		shader:   shader.New(path),
+		enabled:  enabled,
+		update:   update,
+		idle:     idle,
+		drawOpts: ebiten.DrawRectShaderOptions{Uniforms: uniforms},
+	}
+}
+
+// postEffectSettings are the switches of the post effects.
+type postEffectSettings struct {
+	CRT       bool `json:"crt"`
+	Bloom     bool `json:"bloom"`
+	Chromatic bool `json:"chromatic"` // Chromatic aberration on death
+	Vignette  bool `json:"vignette"`
+	PauseBlur bool `json:"pauseBlur"`
+}
+
+var (
+	postEffectsOn = postEffectSettings{Chromatic: true, PauseBlur: true}
+	postEffects   []postEffect
+
+	// Game state driving the uniforms
+	postDeathTime  = float32(chromaticFadeTime) // Seconds after the game is over
+	postGameOver   bool
+	postBlurRadius float32
+
+	postTargets [2]*ebiten.Image // Ping-pong render targets
+	postActive  []*postEffect    // Enabled effects of the frame
+	postColors  [3][3]float32    // Theme colors of the bloom, updated in place
+)
+
+func init() {
+	if err := settings.Load(settingsPostEffects, &postEffectsOn); err != nil {
+		fmt.Println("Could not load the post effect settings:", err)
+	}
+
+	// Effects are applied in this order.
+	postEffects = []postEffect{
+		newPostEffect("Bloom", shader.PathBloom, &postEffectsOn.Bloom, map[string]interface{}{
+			"ColorSnake1": postColors[0][:],
+			"ColorSnake2": postColors[1][:],
+			"ColorFood":   postColors[2][:],
+			"Radius":      float32(bloomRadius),
+			"Intensity":   float32(bloomIntensity),
+		}, func(map[string]interface{}) {
+			setColorVec3(&postColors[0], param.ColorSnake1)
+			setColorVec3(&postColors[1], param.ColorSnake2)
+			setColorVec3(&postColors[2], param.ColorFood)
+		}, nil),
+		newPostEffect("Pause blur", shader.PathBlur, &postEffectsOn.PauseBlur, map[string]interface{}{
+			"Radius": float32(0),
+		}, func(uniforms map[string]interface{}) {
+			uniforms["Radius"] = postBlurRadius
+		}, func() bool {
+			return postBlurRadius < pauseBlurIdleRadius
+		}),
+		newPostEffect("Chromatic aberration", shader.PathChromatic, &postEffectsOn.Chromatic, map[string]interface{}{
+			"Amount": float32(0),
+		}, func(uniforms map[string]interface{}) {
+			amount := float32(0)
+			if postDeathTime < chromaticFadeTime {
+				amount = chromaticMaxAmount * (1 - postDeathTime/chromaticFadeTime)
+			}
+			uniforms["Amount"] = amount
+		}, func() bool {
+			return postDeathTime >= chromaticFadeTime
+		}),
+		newPostEffect("Vignette", shader.PathVignette, &postEffectsOn.Vignette, map[string]interface{}{
+			"Strength": float32(vignetteStrength),
+		}, nil, nil),
+		newPostEffect("CRT", shader.PathCRT, &postEffectsOn.CRT, map[string]interface{}{
+			"Curvature":     float32(crtCurvature),
+			"ScanlineAlpha": float32(crtScanlineAlpha),
+		}, nil, nil),
+	}
+}
+
+// updatePostEffects drives the uniforms of the effects with the state of the current scene.
+func updatePostEffects(curScene scene) {
+	var targetBlur float32
+	postDeathTime += float32(param.DeltaTime)
+
+	game, ok := curScene.(*gameScene)
+	if ok && game.paused {
+		targetBlur = pauseBlurRadius
+	}
+	if ok && game.gameOver && !postGameOver {
+		postDeathTime = 0
+	}
+	postGameOver = ok && game.gameOver
+
+	postBlurRadius += (targetBlur - postBlurRadius) * pauseBlurRate
+}
+
+// drawWithPostEffects draws the scene into an offscreen image and runs it through the enabled effects.
+// Effects at their identity values are skipped, and the scene is drawn directly if none is left.
+func drawWithPostEffects(screen *ebiten.Image, draw func(*ebiten.Image)) {
+	active := postActive[:0]
+	for iEffect := range postEffects {
+		effect := &postEffects[iEffect]
+		if *effect.enabled && ((effect.idle == nil) || !effect.idle()) {
+			active = append(active, effect)
+		}
+	}
+	postActive = active
+	if len(active) == 0 {
+		draw(screen)
+		return
+	}
+
+	// (Re)create the render targets when the screen size changes.
+	for iTarget, target := range postTargets {
+		if (target == nil) || (target.Bounds().Dx() != param.ScreenWidth) || (target.Bounds().Dy() != param.ScreenHeight) {
+			if target != nil {
+				target.Dispose()
+			}
+			postTargets[iTarget] = ebiten.NewImage(param.ScreenWidth, param.ScreenHeight)
+		}
+	}
+
+	src := postTargets[0]
+	src.Clear()
+	draw(src)
+
+	for iEffect, effect := range active {
+		dst := screen
+		if iEffect < len(active)-1 {
+			dst = postTargets[(iEffect+1)%2]
+			dst.Clear()
+		}
+
+		if effect.update != nil {
+			effect.update(effect.drawOpts.Uniforms)
+		}
+		effect.drawOpts.Images[0] = src
+		dst.DrawRectShader(param.ScreenWidth, param.ScreenHeight, effect.shader, &effect.drawOpts)
+		src = dst
+	}
+}
+
+func togglePostEffect(effect *postEffect) {
+	*effect.enabled = !*effect.enabled
+	if err := settings.Save(settingsPostEffects, &postEffectsOn); err != nil {
+		fmt.Println("Could not save the post effect settings:", err)
+	}
+}
+
+func setColorVec3(vec *[3]float32, clr color.RGBA) {
+	vec[0], vec[1], vec[2] = float32(clr.R)/255, float32(clr.G)/255, float32(clr.B)/255
+}
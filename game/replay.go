@@ -0,0 +1,99 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"encoding/json"
+	"path"
+	"time"
+
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+)
+
+const replaysDir = "replays"
+
+// replay records what is needed to play a game again: the start state of the snake, the turn inputs and
+// the food positions, each with the tick it happened at.
+type replay struct {
+	Date        time.Time    `json:"date"`
+	WorldWidth  int          `json:"worldWidth"`
+	WorldHeight int          `json:"worldHeight"`
+	TurnPolicy  s.TurnPolicy `json:"turnPolicy"`
+	Head        c.Vec64      `json:"head"`
+	Direction   s.DirectionT `json:"direction"`
+	Length      float64      `json:"length"`
+	Speed       float64      `json:"speed"`
+	SimRate     int          `json:"simRate"` // Ticks per second
+	Mode        string       `json:"mode"`
+	Turns       []replayTurn `json:"turns"`
+	Foods       []replayFood `json:"foods"`
+	Ticks       int          `json:"ticks"`
+	Score       int          `json:"score"`
+}
+
+type replayTurn struct {
+	Tick      int          `json:"tick"`
+	Direction s.DirectionT `json:"direction"`
+}
+
+type replayFood struct {
+	Tick int     `json:"tick"`
+	Pos  c.Vec32 `json:"pos"`
+}
+
+func newReplay(snake *s.Snake, mode *gameMode) *replay {
+	return &replay{
+		Date:        time.Now(),
+		WorldWidth:  param.WorldWidth,
+		WorldHeight: param.WorldHeight,
+		TurnPolicy:  *snake.TurnPolicy,
+		Head:        snake.UnitHead.HeadCenter,
+		Direction:   snake.UnitHead.Direction,
+		Length:      snake.Length(),
+		Speed:       snake.Speed,
+		SimRate:     param.SimRate,
+		Mode:        mode.id,
+	}
+}
+
+func (r *replay) addTurn(tick int, direction s.DirectionT) {
+	r.Turns = append(r.Turns, replayTurn{tick, direction})
+}
+
+func (r *replay) addFood(tick int, pos c.Vec32) {
+	r.Foods = append(r.Foods, replayFood{tick, pos})
+}
+
+func (r *replay) finish(ticks, score int) {
+	r.Ticks = ticks
+	r.Score = score
+}
+
+// save writes the replay next to the settings file and returns its location.
+func (r *replay) save() (string, error) {
+	data, err := json.MarshalIndent(r, "", "\t")
+	if err != nil {
+		return "", err
+	}
+	return settings.WriteUserFile(path.Join(replaysDir, r.Date.Format("2006-01-02_15-04-05")+".json"), data)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"math"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+// Scoring parameters
+const (
+	comboWindow      = 4.0 // Seconds the multiplier lasts after eating
+	comboStep        = 0.5 // Multiplier increase per food eaten in the combo
+	comboMax         = 4.0 // Maximum multiplier
+	nearMissGap      = 6.0 // The head passes its body closer than this many pixels without hitting it
+	nearMissScore    = 25  // Multiplied by the combo multiplier
+	nearMissCooldown = 1.0 // Seconds after a near miss before the next one counts
+	wrapStyleWindow  = 1.0 // Seconds after a wrap the food gives style points
+	wrapStyleScore   = 150
+)
+
+// Combo HUD parameters
+const (
+	comboTextShiftY = 8
+	comboBarShiftX  = 12
+	comboBarWidth   = 120
+	comboBarHeight  = 8
+)
+
+// scoring keeps the score of a game. Food eaten in quick succession builds a combo that multiplies
+// the points, and it decays if no food is eaten in the combo window.
+type scoring struct {
+	score      int
+	multiplier float64
+	comboTime  float64 // Remaining seconds of the combo
+	streak     int     // Food eaten in the current combo
+	bestStreak int
+	sinceWrap  float64 // Seconds since the head has wrapped around the world
+	nearBody   bool    // The head is near its body
+	nearHit    bool    // The head has overlapped its body since it has come near
+	nearTime   float64 // Remaining seconds of the near miss cooldown
+}
+
+func newScoring() scoring {
+	return scoring{multiplier: 1, sinceWrap: math.Inf(1)}
+}
+
+// update runs the timers in every step.
+func (sc *scoring) update() {
+	sc.sinceWrap += param.DeltaTime
+	sc.nearTime -= param.DeltaTime
+	if sc.comboTime -= param.DeltaTime; sc.comboTime <= 0 {
+		sc.comboTime = 0
+		sc.multiplier = 1
+		sc.streak = 0
+	}
+}
+
+func (sc *scoring) wrapped() {
+	sc.sinceWrap = 0
+}
+
+// eatFood adds the points of the food and returns them. The food is scored with the current multiplier,
+// then the combo goes on.
+func (sc *scoring) eatFood() int {
+	points := int(math.Round(param.FoodScore * sc.multiplier))
+	if sc.sinceWrap <= wrapStyleWindow {
+		points += wrapStyleScore
+	}
+	sc.score += points
+
+	sc.multiplier = math.Min(sc.multiplier+comboStep, comboMax)
+	sc.comboTime = comboWindow
+	sc.streak++
+	if sc.streak > sc.bestStreak {
+		sc.bestStreak = sc.streak
+	}
+	return points
+}
+
+// checkNearMiss adds the bonus of a near miss and returns its points when the head leaves its body
+// without having overlapped it. It returns zero otherwise.
+func (sc *scoring) checkNearMiss(near, overlaps bool) int {
+	sc.nearHit = sc.nearHit || overlaps
+	left := sc.nearBody && !near
+	sc.nearBody = near
+	if !left {
+		return 0
+	}
+
+	hit := sc.nearHit
+	sc.nearHit = false
+	if hit || (sc.nearTime > 0) {
+		return 0
+	}
+
+	sc.nearTime = nearMissCooldown
+	points := int(math.Round(nearMissScore * sc.multiplier))
+	sc.score += points
+	return points
+}
+
+// resetNearMiss forgets that the head is near its body, so leaving it does not give a bonus.
+func (sc *scoring) resetNearMiss() {
+	sc.nearBody, sc.nearHit = false, false
+}
+
+// comboDecay returns the remaining part of the combo window.
+func (sc *scoring) comboDecay() float64 {
+	return sc.comboTime / comboWindow
+}
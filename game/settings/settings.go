@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package settings
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// fileName is the name of the settings file (or the local storage key in the browser).
+const fileName = "settings.json"
+
+var (
+	sections map[string]json.RawMessage
+	mutex    sync.Mutex
+)
+
+// Load decodes the section named name from the settings file into v.
+// v is left untouched if the section does not exist yet.
+func Load(name string, v interface{}) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := readSections(); err != nil {
+		return err
+	}
+
+	raw, ok := sections[name]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Save encodes v as the section named name and writes the whole settings file.
+// It refuses to write if the existing file could not be read or parsed, so that its other sections are kept.
+func Save(name string, v interface{}) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := readSections(); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	sections[name] = raw
+
+	data, err := json.MarshalIndent(sections, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = writeFile(fileName, data)
+	return err
+}
+
+// WriteUserFile stores the data under the name (a slash separated path) next to the settings file.
+// It returns the location of the file for the user.
+func WriteUserFile(name string, data []byte) (string, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return writeFile(name, data)
+}
+
+// AppendUserFile appends the data to the file under the name next to the settings file, it is created if
+// it does not exist. It returns the location of the file for the user.
+func AppendUserFile(name string, data []byte) (string, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return appendFile(name, data)
+}
+
+// readSections reads the settings file once and caches its sections. The sections are cached only if the
+// file is read and parsed, so a file that could not be parsed is not overwritten by Save.
+func readSections() error {
+	if sections != nil {
+		return nil
+	}
+
+	data, err := readFile(fileName)
+	if err != nil {
+		return err
+	}
+	parsed := make(map[string]json.RawMessage)
+	if len(data) > 0 {
+		if err = json.Unmarshal(data, &parsed); err != nil {
+			return err
+		}
+	}
+	sections = parsed
+	return nil
+}
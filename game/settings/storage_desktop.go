@@ -0,0 +1,89 @@
+//go:build !js
+
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package settings
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const dirName = "ssnake"
+
+// Dir returns the directory where the settings and other user data are stored.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, dirName), nil
+}
+
+func readFile(name string) ([]byte, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// writeFile writes the file to the user directory and returns its path.
+func writeFile(name string, data []byte) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, data, 0o644)
+}
+
+// appendFile appends to the file in the user directory and returns its path.
+func appendFile(name string, data []byte) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err = file.Write(data); err != nil {
+		file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
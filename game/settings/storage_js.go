@@ -0,0 +1,93 @@
+//go:build js
+
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+	"unicode/utf8"
+)
+
+const keyPrefix = "ssnake/"
+
+// Dir returns an error in the browser, there is no file system to store user data.
+func Dir() (string, error) {
+	return "", errors.New("settings: no user directory in the browser")
+}
+
+func readFile(name string) ([]byte, error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil, nil
+	}
+
+	item := storage.Call("getItem", keyPrefix+name)
+	if item.IsNull() {
+		return nil, nil
+	}
+	return []byte(item.String()), nil
+}
+
+// writeFile stores the file in the local storage and returns its key.
+func writeFile(name string, data []byte) (string, error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return "", errors.New("settings: local storage is not available")
+	}
+	if !utf8.Valid(data) {
+		// The local storage keeps strings, a binary file like a PNG would be corrupted.
+		return "", errors.New("settings: binary files are not supported in the browser")
+	}
+
+	if err := setItem(storage, keyPrefix+name, string(data)); err != nil {
+		return "", err
+	}
+	return keyPrefix + name, nil
+}
+
+// setItem stores the item in the local storage. The exception thrown when the storage is full is returned
+// as an error instead of a panic.
+func setItem(storage js.Value, key, value string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			jsErr, ok := r.(js.Error)
+			if !ok {
+				panic(r)
+			}
+			err = fmt.Errorf("settings: could not store %s: %w", key, jsErr)
+		}
+	}()
+
+	storage.Call("setItem", key, value)
+	return nil
+}
+
+// appendFile appends to the item in the local storage and returns its key. The local storage cannot
+// append, so the item is read and stored again.
+func appendFile(name string, data []byte) (string, error) {
+	existing, err := readFile(name)
+	if err != nil {
+		return "", err
+	}
+	return writeFile(name, append(existing, data...))
+}
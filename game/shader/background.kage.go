@@ -0,0 +1,19 @@
+//go:build ignore
+
+package main
+
+var (
+	Color      vec4
+	ScreenSize vec2
+)
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	uv := position.xy / ScreenSize
+
+	// Darken towards the bottom and the corners
+	shade := 1.0 - 0.2*uv.y
+	dist := distance(uv, vec2(0.5))
+	vignette := 1.0 - 0.4*dist*dist
+
+	return vec4(Color.rgb*shade*vignette, 1.0)
+}
@@ -0,0 +1,37 @@
+//go:build ignore
+
+package main
+
+var (
+	ColorSnake1 vec3
+	ColorSnake2 vec3
+	ColorFood   vec3
+	Radius      float
+	Intensity   float
+)
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	clr := imageSrc0UnsafeAt(texCoord)
+	pixelSize := 1.0 / imageSrcTextureSize()
+
+	// Sum the colors of the snake and food pixels around in three rings
+	glow := vec3(0.0)
+	for ring := 1; ring <= 3; ring++ {
+		dist := Radius * float(ring) / 3.0
+		for i := 0; i < 12; i++ {
+			angle := float(i)*0.5235988 + float(ring)*0.2617994
+			sample := imageSrc0At(texCoord + vec2(cos(angle), sin(angle))*dist*pixelSize)
+			glow += sample.rgb * glowMask(sample.rgb) / float(ring)
+		}
+	}
+
+	clr.rgb += glow * Intensity / 22.0
+	return clr
+}
+
+// glowMask returns 1 for the colors of the snake and the food, and 0 for the rest.
+func glowMask(clr vec3) float {
+	mask := max(1.0-4.0*distance(clr, ColorSnake1), 1.0-4.0*distance(clr, ColorSnake2))
+	mask = max(mask, 1.0-4.0*distance(clr, ColorFood))
+	return clamp(mask, 0.0, 1.0)
+}
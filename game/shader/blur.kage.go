@@ -0,0 +1,22 @@
+//go:build ignore
+
+package main
+
+var Radius float // pixels
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	if Radius <= 0.0 {
+		return imageSrc0UnsafeAt(texCoord)
+	}
+
+	// Average a 7x7 grid of samples spread over the radius
+	pixelSize := 1.0 / imageSrcTextureSize()
+	sum := vec4(0.0)
+	for x := -3; x <= 3; x++ {
+		for y := -3; y <= 3; y++ {
+			offset := vec2(float(x), float(y)) / 3.0 * Radius
+			sum += imageSrc0At(texCoord + offset*pixelSize)
+		}
+	}
+	return sum / 49.0
+}
@@ -0,0 +1,19 @@
+//go:build ignore
+
+package main
+
+var Amount float // Shift of the red and blue channels in pixels at the screen corners
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	origin, size := imageSrcRegionOnTexture()
+	pixelSize := 1.0 / imageSrcTextureSize()
+
+	// Shift the channels away from the center
+	dir := (texCoord-origin)/size - 0.5
+	shift := dir * 2.0 * Amount * pixelSize
+
+	clr := imageSrc0UnsafeAt(texCoord)
+	clr.r = imageSrc0At(texCoord + shift).r
+	clr.b = imageSrc0At(texCoord - shift).b
+	return clr
+}
@@ -0,0 +1,29 @@
+//go:build ignore
+
+package main
+
+var (
+	Curvature     float
+	ScanlineAlpha float
+)
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	origin, size := imageSrcRegionOnTexture()
+	uv := (texCoord - origin) / size
+
+	// Bend the screen like the glass of a tube
+	centered := uv*2.0 - 1.0
+	centered *= 1.0 + Curvature*dot(centered.yx, centered.yx)
+	uv = centered*0.5 + 0.5
+	if uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0 {
+		return vec4(0.0, 0.0, 0.0, 1.0)
+	}
+	clr := imageSrc0At(origin + uv*size)
+
+	// Darken every other line
+	if mod(floor(position.y), 2.0) == 1.0 {
+		clr.rgb *= 1.0 - ScanlineAlpha
+	}
+
+	return clr
+}
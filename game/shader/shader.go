@@ -7,10 +7,17 @@ import (
 )
 
 const (
+	PathBackground  = "background.kage.go"
 	PathBasic       = "basic.kage.go"
+	PathBloom       = "bloom.kage.go"
+	PathBlur        = "blur.kage.go"
+	PathChromatic   = "chromatic.kage.go"
 	PathCircle      = "circle.kage.go"
 	PathCircleMouth = "circlemouth.kage.go"
+	PathCRT         = "crt.kage.go"
+	PathSkin        = "skin.kage.go"
 	PathTitle       = "title.kage.go"
+	PathVignette    = "vignette.kage.go"
 )
 
 var (
@@ -0,0 +1,188 @@
+//go:build ignore
+
+package main
+
+// The part of the snake is in the red channel of the vertex color:
+// 0: body, 1: head, 2: joint, 3: tail, 4: arc of a left turn, 5: arc of a right turn.
+// Body vertices have the material coordinate along the body and the coordinate across it in texCoord,
+// and the range of the visible material coordinates in the green and blue channels.
+// Circle parts have the position in their square in texCoord, and joints have the material coordinate
+// of their centers in the green channel.
+// Arc vertices have the position relative to the arc center along the incoming and against the outgoing
+// direction in texCoord, the material coordinate at the turn in the green channel and the clip limits of
+// the coordinates in the blue and alpha channels.
+
+var (
+	Radius        float
+	RadiusMouth   float
+	Direction     float // Head direction
+	ProxToFood    float
+	TailDirection float
+	Traveled      float // Material coordinate at the head center, the distance traveled modulo the pattern period
+	TotalLength   float // Length from the head center to the tail center
+	HeadBody      float // Visible body length behind the head center
+	TailBody      float // Visible body length in front of the tail center
+	HeadColor     vec4
+	TailColor     vec4
+	Pattern       float // 0: none, 1: stripes, 2: dots, 3: scales
+	PatternColor  vec4
+	PatternPeriod float
+	PatternWidth  float
+	Shading       float
+	Eyes          float
+	EyeColor      vec4
+	PupilColor    vec4
+	TailTip       float // 0: round, 1: pointed, 2: fade
+)
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	part := floor(color.r + 0.5)
+
+	var clr vec4
+	if part == 0.0 {
+		if texCoord.x > color.g || texCoord.x < color.b {
+			return vec4(0.0)
+		}
+		clr = body(texCoord)
+	} else if part == 1.0 {
+		clr = head(texCoord - vec2(Radius))
+	} else if part == 2.0 {
+		clr = circle(texCoord-vec2(Radius), gradient(color.g))
+	} else if part == 3.0 {
+		clr = tail(texCoord - vec2(Radius))
+	} else {
+		clr = arc(texCoord, color, part == 4.0)
+	}
+
+	clr.rgb *= clr.a
+	return clr
+}
+
+// gradient returns the position of the material coordinate in the color gradient.
+func gradient(material float) float {
+	return clamp((Traveled-material)/TotalLength, 0.0, 1.0)
+}
+
+// arc draws the quarter annulus at a turn by mapping it to the straight body.
+func arc(p vec2, attribs vec4, left bool) vec4 {
+	dist := length(p)
+	if dist > 2.0*Radius || p.x < attribs.b || p.y < attribs.a {
+		return vec4(0.0)
+	}
+
+	// The material coordinate goes from a radius behind the turn to a radius in front of it.
+	material := attribs.g - Radius + 4.0*Radius*atan2(p.x, p.y)/3.14159265
+	across := dist // Distance from the inner side
+	if !left {
+		across = 2.0*Radius - dist
+	}
+	return body(vec2(material, across))
+}
+
+func body(tex vec2) vec4 {
+	clr := mix(HeadColor, TailColor, gradient(tex.x))
+
+	// Pattern cells are fixed on the body, so they scroll with the movement.
+	cell := fract(tex.x / PatternPeriod)
+	across := tex.y - Radius
+	if Pattern == 1.0 {
+		if cell < PatternWidth {
+			clr = PatternColor
+		}
+	} else if Pattern == 2.0 {
+		if length(vec2((cell-0.5)*PatternPeriod, across)) < PatternWidth*Radius {
+			clr = PatternColor
+		}
+	} else if Pattern == 3.0 {
+		row := floor(tex.y / Radius)
+		scaleCell := fract(tex.x/PatternPeriod + row*0.5)
+		dist := length(vec2((scaleCell-0.5)*PatternPeriod, fract(tex.y/Radius)*Radius))
+		if abs(dist-PatternPeriod*0.5) < PatternWidth*PatternPeriod*0.5 {
+			clr = PatternColor
+		}
+	}
+
+	side := across / Radius
+	clr.rgb *= 1.0 - Shading*side*side
+	return clr
+}
+
+// circle draws a shaded disk of the gradient color at t.
+func circle(p vec2, t float) vec4 {
+	dist := length(p)
+	if dist > Radius {
+		return vec4(0.0)
+	}
+
+	clr := mix(HeadColor, TailColor, t)
+	side := dist / Radius
+	clr.rgb *= 1.0 - Shading*side*side
+	return clr
+}
+
+func head(p vec2) vec4 {
+	forward := directionVector(Direction)
+	local := vec2(dot(p, forward), dot(p, vec2(-forward.y, forward.x))) // forward, side
+
+	// The back half is covered by the body, leave its pattern visible.
+	if local.x < 0.0 && -local.x < HeadBody {
+		return vec4(0.0)
+	}
+	clr := circle(p, 0.0)
+
+	// Open the mouth towards the food
+	if ProxToFood > 0.0 && distance(local, vec2(Radius, 0.0)) < RadiusMouth*easeOutCubic(ProxToFood) {
+		return vec4(0.0)
+	}
+
+	if Eyes == 1.0 {
+		eye := vec2(Radius*0.2, Radius*0.45)
+		local.y = abs(local.y)
+		if distance(local, eye+vec2(Radius*0.1, 0.0)) < Radius*0.13 {
+			clr = PupilColor
+		} else if distance(local, eye) < Radius*0.27 {
+			clr = EyeColor
+		}
+	}
+
+	return clr
+}
+
+func tail(p vec2) vec4 {
+	forward := directionVector(TailDirection)
+	toTip := -dot(p, forward)
+	side := abs(dot(p, vec2(-forward.y, forward.x)))
+
+	// The front half is covered by the body, leave its pattern visible.
+	if toTip < 0.0 && -toTip < TailBody {
+		return vec4(0.0)
+	}
+
+	clr := circle(p, 1.0)
+	if TailTip == 1.0 {
+		if side > Radius-toTip {
+			return vec4(0.0)
+		}
+		clr = TailColor
+	} else if TailTip == 2.0 {
+		clr.a *= 1.0 - max(toTip, 0.0)/Radius
+	}
+	return clr
+}
+
+func directionVector(direction float) vec2 {
+	if direction == 0.0 {
+		return vec2(0.0, -1.0)
+	} else if direction == 1.0 {
+		return vec2(0.0, 1.0)
+	} else if direction == 2.0 {
+		return vec2(-1.0, 0.0)
+	}
+	return vec2(1.0, 0.0)
+}
+
+// https://easings.net/#easeOutCubic
+func easeOutCubic(x float) float {
+	xMin := 1.0 - x
+	return 1.0 - xMin*xMin*xMin
+}
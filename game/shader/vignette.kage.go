@@ -0,0 +1,16 @@
+//go:build ignore
+
+package main
+
+var Strength float
+
+func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+	origin, size := imageSrcRegionOnTexture()
+	uv := (texCoord - origin) / size
+
+	// Darken towards the corners
+	dist := distance(uv, vec2(0.5))
+	clr := imageSrc0UnsafeAt(texCoord)
+	clr.rgb *= 1.0 - Strength*smoothstep(0.3, 0.75, dist)
+	return clr
+}
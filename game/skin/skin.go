@@ -0,0 +1,194 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package skin holds the snake skins: the body gradient, the pattern that scrolls with the movement, the
+// eyes and the tail tip. The built-in skins are embedded json files and the user can add more to the
+// skins folder next to the settings file.
+package skin
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/anilkonac/snake-ebiten/game/theme"
+)
+
+const (
+	settingsSection = "skin"
+	userSkinsDir    = "skins"
+)
+
+// Pattern names
+const (
+	PatternNone    = "none"
+	PatternStripes = "stripes"
+	PatternDots    = "dots"
+	PatternScales  = "scales"
+)
+
+// Tail tip names
+const (
+	TailRound   = "round"
+	TailPointed = "pointed"
+	TailFade    = "fade"
+)
+
+type Skin struct {
+	Name          string      `json:"name"`
+	ThemeColors   bool        `json:"themeColors"` // Use the snake's own color instead of the head and tail colors
+	HeadColor     theme.Color `json:"headColor"`   // The body is a gradient from the head color to the tail color
+	TailColor     theme.Color `json:"tailColor"`
+	Pattern       string      `json:"pattern"`
+	PatternColor  theme.Color `json:"patternColor"`
+	PatternPeriod float32     `json:"patternPeriod"` // Length of a pattern cell along the body in pixels
+	PatternWidth  float32     `json:"patternWidth"`  // Ratio of the pattern in a cell
+	Shading       float32     `json:"shading"`       // Darkening towards the sides of the body
+	Eyes          bool        `json:"eyes"`
+	EyeColor      theme.Color `json:"eyeColor"`
+	PupilColor    theme.Color `json:"pupilColor"`
+	TailTip       string      `json:"tailTip"`
+}
+
+type savedSettings struct {
+	Player string `json:"player"`
+}
+
+var (
+	//go:embed skins/*.json
+	fs     embed.FS
+	skins  []Skin
+	player = 0
+)
+
+func init() {
+	var err error
+	if skins, err = loadSkins(); err != nil {
+		panic(err)
+	}
+
+	userSkins, err := loadUserSkins()
+	if err != nil {
+		fmt.Println("skin: could not load the user skins:", err)
+	}
+	skins = append(skins, userSkins...)
+
+	var saved savedSettings
+	if err := settings.Load(settingsSection, &saved); err != nil {
+		fmt.Println("skin: could not load the skin settings:", err)
+	}
+	for iSkin := range skins {
+		if skins[iSkin].Name == saved.Player {
+			player = iSkin
+		}
+	}
+}
+
+// Default returns the plain skin that is drawn with the snake's color.
+func Default() *Skin {
+	return &skins[0]
+}
+
+// Player returns the skin of the player's snake.
+func Player() *Skin {
+	return &skins[player]
+}
+
+// Random returns one of the skins.
+func Random() *Skin {
+	return &skins[rand.Intn(len(skins))]
+}
+
+// SelectPlayer changes the player's skin by the offset in the skin list and saves the choice.
+func SelectPlayer(offset int) *Skin {
+	player = ((player+offset)%len(skins) + len(skins)) % len(skins)
+
+	if err := settings.Save(settingsSection, savedSettings{Player: skins[player].Name}); err != nil {
+		fmt.Println("skin: could not save the skin settings:", err)
+	}
+	return &skins[player]
+}
+
+// loadSkins reads the built-in skins in the order of their file names. The first one is the default skin.
+func loadSkins() ([]Skin, error) {
+	entries, err := fs.ReadDir("skins")
+	if err != nil {
+		return nil, err
+	}
+
+	var builtinSkins []Skin
+	for _, entry := range entries {
+		data, err := fs.ReadFile(path.Join("skins", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var skin Skin
+		if err = json.Unmarshal(data, &skin); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		builtinSkins = append(builtinSkins, skin)
+	}
+	return builtinSkins, nil
+}
+
+// loadUserSkins reads the json skin files in the user's skins folder.
+// Missing fields are taken from the default skin.
+func loadUserSkins() ([]Skin, error) {
+	dir, err := settings.Dir()
+	if err != nil {
+		return nil, nil // No user folder on this platform
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, userSkinsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var userSkins []Skin
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, userSkinsDir, entry.Name()))
+		if err != nil {
+			return userSkins, err
+		}
+
+		skin := skins[0]
+		if err = json.Unmarshal(data, &skin); err != nil {
+			return userSkins, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if skin.Name == skins[0].Name {
+			skin.Name = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		}
+		userSkins = append(userSkins, skin)
+	}
+	return userSkins, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/input"
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/skin"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Skins scene parameters
+const (
+	textSkinsTitle      = "Skins"
+	textSkinsHelp       = "%s/%s: Change   %s: Back"
+	skinNameShiftY      = 110
+	skinPreviewLength   = 360
+	skinPreviewSpeed    = 180
+	skinPreviewTurnTime = 0.9 // seconds
+	skinPreviewSide     = skinPreviewSpeed * skinPreviewTurnTime
+)
+
+// skinsScene previews the player's skin on a snake running in a square loop.
+type skinsScene struct {
+	preview  *s.Snake
+	turnTime float32
+}
+
+func newSkinsScene() *skinsScene {
+	// Look at the top left corner of the world, so the screen and world coordinates are the same.
+	center := c.Vec64{X: float64(param.ScreenWidth) / 2.0, Y: float64(param.ScreenHeight) / 2.0}
+	c.Cam.Reset(center)
+
+	preview := s.NewSnake(c.Vec64{X: center.X + skinPreviewSide/2.0, Y: center.Y + skinPreviewSide/2.0},
+		skinPreviewLength, skinPreviewSpeed, s.DirectionRight, &param.ColorSnake1)
+	preview.Skin = skin.Player()
+
+	return &skinsScene{preview: preview}
+}
+
+func (k *skinsScene) update() bool {
+	switch {
+	case input.IsActionJustPressed(input.ActionMenuPrev):
+		k.preview.Skin = skin.SelectPlayer(-1)
+	case input.IsActionJustPressed(input.ActionMenuNext):
+		k.preview.Skin = skin.SelectPlayer(+1)
+	case input.IsActionJustPressed(input.ActionMenuBack):
+		return true
+	}
+
+	// Turn left at the corners of the loop
+	k.turnTime += float32(param.DeltaTime)
+	if k.turnTime >= skinPreviewTurnTime {
+		k.turnTime -= skinPreviewTurnTime
+		dirCurrent := k.preview.LastDirection()
+		dirNew := [s.DirectionTotal]s.DirectionT{
+			s.DirectionUp:    s.DirectionLeft,
+			s.DirectionLeft:  s.DirectionDown,
+			s.DirectionDown:  s.DirectionRight,
+			s.DirectionRight: s.DirectionUp,
+		}[dirCurrent]
+		k.preview.TurnTo(s.NewTurn(dirCurrent, dirNew), false)
+	}
+	k.preview.Update(param.MouthAnimStartDistance)
+
+	return false
+}
+
+func (k *skinsScene) draw(screen *ebiten.Image) {
+	drawBackground(screen)
+
+	// Draw title
+	boundTitle := text.BoundString(param.FontFaceScore, textSkinsTitle)
+	text.Draw(screen, textSkinsTitle, param.FontFaceScore,
+		(param.ScreenWidth-boundTitle.Size().X)/2-boundTitle.Min.X, controlsTitleShiftY-boundTitle.Min.Y, param.ColorScore)
+
+	// Draw skin name
+	name := "< " + k.preview.Skin.Name + " >"
+	boundName := text.BoundString(fontFaceMenu, name)
+	text.Draw(screen, name, fontFaceMenu, (param.ScreenWidth-boundName.Size().X)/2-boundName.Min.X, skinNameShiftY, param.ColorDebug)
+
+	k.preview.Draw(screen)
+
+	text.Draw(screen, pagesHelp(textSkinsHelp), fontFaceDebug, controlsLabelX, param.ScreenHeight-controlsHelpShiftY, param.ColorDebug)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sound
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	adaptiveFadeSec  = 1.0     // Time for a stem gain to go from silent to full
+	cutoffSweepSec   = 0.5     // Time for a low-pass filter to sweep between its limits
+	cutoffOpen       = 20000.0 // Filter cutoff that is treated as no filtering
+	cutoffMin        = 100.0
+	bytesPerFrame    = 4 // 16 bit stereo
+	adaptiveChannels = 2
+)
+
+// AdaptiveMusic is a stream processor mixing looping stems with individually controlled gains and low-pass
+// filters. A master low-pass filter is applied to the mix. It is played through a Music created with
+// NewStreamMusic, so it works wherever the audio context works.
+type AdaptiveMusic struct {
+	mutex   sync.Mutex
+	stems   []*stem
+	master  lowPass
+	scratch []byte
+}
+
+type stem struct {
+	src        io.Reader
+	gain       float64
+	gainTarget float64
+	filter     lowPass
+}
+
+// lowPass is a one pole low-pass filter whose cutoff frequency sweeps smoothly to its target.
+type lowPass struct {
+	cutoff       float64
+	cutoffTarget float64
+	state        [adaptiveChannels]float64
+}
+
+// NewAdaptiveMusic creates an empty adaptive music. Stems are added with AddStem.
+func NewAdaptiveMusic() *AdaptiveMusic {
+	return &AdaptiveMusic{
+		master: newLowPass(),
+	}
+}
+
+func newLowPass() lowPass {
+	return lowPass{cutoff: cutoffOpen, cutoffTarget: cutoffOpen}
+}
+
+// AddStem adds a stem looping the first length bytes of the 16 bit stereo PCM stream and returns its index.
+func (a *AdaptiveMusic) AddStem(stream io.ReadSeeker, length int64, gain float64) int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.stems = append(a.stems, &stem{
+		src:        audio.NewInfiniteLoop(stream, length),
+		gain:       gain,
+		gainTarget: gain,
+		filter:     newLowPass(),
+	})
+	return len(a.stems) - 1
+}
+
+// SetStemGain fades the stem to the given gain in the range [0, 1].
+func (a *AdaptiveMusic) SetStemGain(iStem int, gain float64) {
+	a.mutex.Lock()
+	a.stems[iStem].gainTarget = math.Max(0, math.Min(gain, 1))
+	a.mutex.Unlock()
+}
+
+// SetStemCutoff sweeps the low-pass filter of the stem to the given cutoff frequency in Hz.
+func (a *AdaptiveMusic) SetStemCutoff(iStem int, cutoff float64) {
+	a.mutex.Lock()
+	a.stems[iStem].filter.cutoffTarget = clampCutoff(cutoff)
+	a.mutex.Unlock()
+}
+
+// SetCutoff sweeps the master low-pass filter to the given cutoff frequency in Hz.
+func (a *AdaptiveMusic) SetCutoff(cutoff float64) {
+	a.mutex.Lock()
+	a.master.cutoffTarget = clampCutoff(cutoff)
+	a.mutex.Unlock()
+}
+
+func clampCutoff(cutoff float64) float64 {
+	return math.Max(cutoffMin, math.Min(cutoff, cutoffOpen))
+}
+
+// Read implements io.Reader. It is called from the audio goroutine.
+func (a *AdaptiveMusic) Read(buf []byte) (int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	numFrames := len(buf) / bytesPerFrame
+	numBytes := numFrames * bytesPerFrame
+	if numFrames == 0 {
+		return 0, nil
+	}
+
+	for iByte := range buf[:numBytes] {
+		buf[iByte] = 0
+	}
+	if cap(a.scratch) < numBytes {
+		a.scratch = make([]byte, numBytes)
+	}
+	scratch := a.scratch[:numBytes]
+
+	// The mix is accumulated in the output buffer as 16 bit samples after each stem is added.
+	const gainStep = 1.0 / (adaptiveFadeSec * SampleRate)
+	for _, st := range a.stems {
+		if _, err := io.ReadFull(st.src, scratch); err != nil {
+			return 0, err
+		}
+		for iFrame := 0; iFrame < numFrames; iFrame++ {
+			st.gain = approach(st.gain, st.gainTarget, gainStep)
+			st.filter.step()
+			for channel := 0; channel < adaptiveChannels; channel++ {
+				offset := iFrame*bytesPerFrame + channel*2
+				sample := float64(int16(binary.LittleEndian.Uint16(scratch[offset:])))
+				sample = st.filter.apply(channel, sample) * st.gain
+				mixed := float64(int16(binary.LittleEndian.Uint16(buf[offset:]))) + sample
+				binary.LittleEndian.PutUint16(buf[offset:], uint16(clampSample(mixed)))
+			}
+		}
+	}
+
+	// Apply the master filter to the mix.
+	for iFrame := 0; iFrame < numFrames; iFrame++ {
+		a.master.step()
+		for channel := 0; channel < adaptiveChannels; channel++ {
+			offset := iFrame*bytesPerFrame + channel*2
+			sample := float64(int16(binary.LittleEndian.Uint16(buf[offset:])))
+			binary.LittleEndian.PutUint16(buf[offset:], uint16(clampSample(a.master.apply(channel, sample))))
+		}
+	}
+
+	return numBytes, nil
+}
+
+// step sweeps the cutoff frequency one frame towards its target. The sweep is exponential so that it sounds even.
+func (l *lowPass) step() {
+	if l.cutoff == l.cutoffTarget {
+		return
+	}
+	ratio := math.Pow(cutoffOpen/cutoffMin, 1.0/(cutoffSweepSec*SampleRate))
+	if l.cutoff < l.cutoffTarget {
+		l.cutoff = math.Min(l.cutoff*ratio, l.cutoffTarget)
+	} else {
+		l.cutoff = math.Max(l.cutoff/ratio, l.cutoffTarget)
+	}
+}
+
+func (l *lowPass) apply(channel int, sample float64) float64 {
+	if l.cutoff >= cutoffOpen {
+		l.state[channel] = sample
+		return sample
+	}
+	alpha := 1 - math.Exp(-2*math.Pi*l.cutoff/SampleRate)
+	l.state[channel] += alpha * (sample - l.state[channel])
+	return l.state[channel]
+}
+
+func approach(value, target, step float64) float64 {
+	if value < target {
+		return math.Min(value+step, target)
+	}
+	return math.Max(value-step, target)
+}
+
+func clampSample(sample float64) int16 {
+	if sample > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if sample < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(sample)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sound
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const maxOneShots = 16
+
+// oneShots are the players of the streams played once with PlayStream.
+var oneShots []*audio.Player
+
+// Effect is a sound effect with several voices, so it can overlap itself when played repeatedly.
+type Effect struct {
+	voices    []*audio.Player
+	volume    float64
+	nextVoice int
+}
+
+// NewEffect decodes the whole stream into memory and creates numVoices players for it.
+// The stream must be 16 bit stereo PCM at SampleRate.
+func NewEffect(stream io.Reader, volume float64, numVoices int) (*Effect, error) {
+	effect := &Effect{volume: volume}
+	if context == nil {
+		return effect, errDisabled
+	}
+
+	pcm, err := io.ReadAll(stream)
+	if err != nil {
+		return effect, err
+	}
+
+	effect.voices = make([]*audio.Player, numVoices)
+	for iVoice := range effect.voices {
+		effect.voices[iVoice] = context.NewPlayerFromBytes(pcm)
+	}
+
+	return effect, nil
+}
+
+// Play plays the effect on a free voice. If all voices are busy, the oldest one is restarted.
+func (e *Effect) Play() {
+	e.PlayWithVolume(1.0)
+}
+
+// PlayWithVolume plays the effect with an additional volume multiplier.
+func (e *Effect) PlayWithVolume(volume float64) {
+	if (len(e.voices) == 0) || current.SFXMuted {
+		return
+	}
+
+	voice := e.voices[e.nextVoice]
+	for iVoice := range e.voices {
+		if candidate := e.voices[(e.nextVoice+iVoice)%len(e.voices)]; !candidate.IsPlaying() {
+			voice = candidate
+			e.nextVoice = (e.nextVoice + iVoice) % len(e.voices)
+			break
+		}
+	}
+	e.nextVoice = (e.nextVoice + 1) % len(e.voices)
+
+	voice.SetVolume(e.volume * volume * busVolume(BusSFX))
+	voice.Rewind()
+	voice.Play()
+}
+
+// PlayStream plays a 16 bit stereo PCM stream once on the SFX bus, such as a synthesized sound effect.
+// The stream is dropped if too many streams are already playing.
+func PlayStream(stream io.Reader, volume float64) {
+	if (context == nil) || current.SFXMuted || (len(oneShots) >= maxOneShots) {
+		return
+	}
+
+	player, err := context.NewPlayer(stream)
+	if err != nil {
+		fmt.Println("sound: could not play the stream:", err)
+		return
+	}
+	player.SetVolume(volume * busVolume(BusSFX))
+	player.Play()
+	oneShots = append(oneShots, player)
+}
+
+// updateOneShots releases the players of the finished streams.
+func updateOneShots() {
+	for iPlayer := len(oneShots) - 1; iPlayer >= 0; iPlayer-- {
+		if player := oneShots[iPlayer]; !player.IsPlaying() {
+			player.Close()
+			oneShots = append(oneShots[:iPlayer], oneShots[iPlayer+1:]...)
+		}
+	}
+}
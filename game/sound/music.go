@@ -0,0 +1,127 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sound
+
+import (
+	"io"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const fadeDurationSec = 0.75
+
+// Music is a seamlessly looping track which is faded in and out instead of being started and stopped abruptly.
+type Music struct {
+	player     *audio.Player
+	track      *Track  // Source track if the music is played from a playlist
+	volume     float64 // Volume of the track itself
+	duration   float64 // Duration in seconds, zero if the music loops
+	gain       float64 // Current fade gain
+	gainTarget float64
+	closing    bool // Close the player when it is faded out
+}
+
+// NewStreamMusic creates a music playing an endless 16 bit stereo PCM stream at SampleRate,
+// such as an AdaptiveMusic.
+func NewStreamMusic(stream io.Reader, volume float64) (*Music, error) {
+	if context == nil {
+		return &Music{volume: volume}, errDisabled
+	}
+	return newMusic(stream, 0, volume)
+}
+
+func newMusic(stream io.Reader, duration, volume float64) (*Music, error) {
+	music := &Music{
+		volume:   volume,
+		duration: duration,
+	}
+
+	player, err := context.NewPlayer(stream)
+	if err != nil {
+		return music, err
+	}
+	music.player = player
+	musics = append(musics, music)
+
+	return music, nil
+}
+
+// remaining returns how many seconds are left until the end of a non-looping music.
+func (m *Music) remaining() float64 {
+	if (m.player == nil) || (m.duration == 0) {
+		return fadeDurationSec + 1
+	}
+	return m.duration - m.player.Current().Seconds()
+}
+
+// fadeOutAndClose fades the music out and releases its player afterwards.
+func (m *Music) fadeOutAndClose() {
+	m.closing = true
+	m.fadeTo(0)
+}
+
+func (m *Music) close() {
+	m.player.Close()
+	m.player = nil
+	for iMusic, music := range musics {
+		if music == m {
+			musics = append(musics[:iMusic], musics[iMusic+1:]...)
+			break
+		}
+	}
+}
+
+func (m *Music) fadeTo(gain float64) {
+	m.gainTarget = gain
+	if (m.player != nil) && (gain > 0) && !m.player.IsPlaying() {
+		m.player.Play()
+	}
+}
+
+func (m *Music) update() {
+	if m.player == nil {
+		return
+	}
+
+	step := param.DeltaTime / fadeDurationSec // Gain change per tick
+	if m.gain < m.gainTarget {
+		m.gain += step
+		if m.gain > m.gainTarget {
+			m.gain = m.gainTarget
+		}
+	} else if m.gain > m.gainTarget {
+		m.gain -= step
+		if m.gain < m.gainTarget {
+			m.gain = m.gainTarget
+		}
+	}
+
+	if m.gain <= 0 {
+		if m.closing {
+			m.close()
+			return
+		}
+		if m.player.IsPlaying() {
+			m.player.Pause()
+		}
+	}
+	m.player.SetVolume(m.volume * m.gain * busVolume(BusMusic))
+}
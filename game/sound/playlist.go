@@ -0,0 +1,171 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sound
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// Playlist plays its tracks one after another with crossfades.
+type Playlist struct {
+	tracks []*Track
+	order  []int // Indices of the tracks in play order
+	pos    int   // Position of the current track in the order
+}
+
+func NewPlaylist(tracks ...*Track) *Playlist {
+	playlist := &Playlist{}
+	playlist.Add(tracks...)
+	return playlist
+}
+
+// Add appends the tracks to the end of the playlist.
+func (p *Playlist) Add(tracks ...*Track) {
+	p.tracks = append(p.tracks, tracks...)
+	p.reorder()
+}
+
+// reorder refreshes the play order according to the shuffle mode, keeping the current track in the same position.
+func (p *Playlist) reorder() {
+	var currentTrack int
+	if p.pos < len(p.order) {
+		currentTrack = p.order[p.pos]
+	}
+
+	p.order = p.order[:0]
+	for iTrack := range p.tracks {
+		p.order = append(p.order, iTrack)
+	}
+	if current.Shuffle {
+		rand.Shuffle(len(p.order), func(i, j int) {
+			p.order[i], p.order[j] = p.order[j], p.order[i]
+		})
+	}
+
+	p.pos = p.indexOf(currentTrack)
+}
+
+func (p *Playlist) indexOf(iTrack int) int {
+	for pos, index := range p.order {
+		if index == iTrack {
+			return pos
+		}
+	}
+	return 0
+}
+
+// Next crossfades to the next track of the playlist.
+func (p *Playlist) Next() {
+	if len(p.tracks) == 0 {
+		return
+	}
+
+	p.pos++
+	if p.pos >= len(p.order) {
+		if current.Shuffle {
+			rand.Shuffle(len(p.order), func(i, j int) {
+				p.order[i], p.order[j] = p.order[j], p.order[i]
+			})
+		}
+		p.pos = 0
+	}
+	p.play()
+}
+
+// play starts the current track. Tracks that cannot be decoded are skipped. The only track of a playlist
+// loops seamlessly instead of being crossfaded into itself.
+func (p *Playlist) play() {
+	for range p.order {
+		track := p.tracks[p.order[p.pos]]
+		music, err := track.newMusic(len(p.tracks) == 1)
+		if err == nil {
+			crossfadeTo(music)
+			return
+		}
+
+		fmt.Printf("sound: could not play %s: %v\n", track.Name, err)
+		p.pos = (p.pos + 1) % len(p.order)
+	}
+
+	// None of the tracks can be played, stop trying.
+	if p == playlistNow {
+		playlistNow = nil
+	}
+}
+
+// PlayPlaylist switches to the given playlist. If the current music is also in the new playlist,
+// it keeps playing and the playlist continues from it.
+func PlayPlaylist(playlist *Playlist) {
+	if (playlist == playlistNow) || (context == nil) {
+		return
+	}
+	playlistNow = playlist
+
+	if musicNow != nil && musicNow.track != nil {
+		for iTrack, track := range playlist.tracks {
+			if track == musicNow.track {
+				playlist.pos = playlist.indexOf(iTrack)
+				return
+			}
+		}
+	}
+
+	if len(playlist.tracks) > 0 {
+		playlist.play()
+	}
+}
+
+// NextTrack skips to the next track of the current playlist.
+func NextTrack() {
+	if playlistNow != nil {
+		playlistNow.Next()
+	}
+}
+
+// CurrentTrackName returns the name of the music being played.
+func CurrentTrackName() string {
+	if (musicNow == nil) || (musicNow.track == nil) {
+		return ""
+	}
+	return musicNow.track.Name
+}
+
+// newMusic decodes the track into a music. A looping music has no end, so it is not followed by the next track.
+func (t *Track) newMusic(loop bool) (*Music, error) {
+	stream, length, err := t.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	var music *Music
+	if loop {
+		music, err = newMusic(audio.NewInfiniteLoop(stream, length), 0, t.volume)
+	} else {
+		music, err = newMusic(stream, float64(length)/bytesPerSecond, t.volume)
+	}
+	if err != nil {
+		return nil, err
+	}
+	music.track = t
+	return music, nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package sound manages the audio context, the volume buses, the music tracks and the sound effects.
+package sound
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	SampleRate      = 44100
+	settingsSection = "audio"
+	volumeStep      = 0.1
+)
+
+// Bus is a volume channel. Every music track and sound effect is played through the music or the SFX bus and
+// all of them through the master bus.
+type Bus uint8
+
+const (
+	BusMaster Bus = iota
+	BusMusic
+	BusSFX
+	BusTotal
+)
+
+var busNames = [BusTotal]string{"Master", "Music", "SFX"}
+
+func (b Bus) String() string {
+	if b >= BusTotal {
+		return "Unknown"
+	}
+	return busNames[b]
+}
+
+// Settings holds the persisted audio preferences.
+type Settings struct {
+	Volumes    [BusTotal]float64 `json:"volumes"`
+	MusicMuted bool              `json:"musicMuted"`
+	SFXMuted   bool              `json:"sfxMuted"`
+	Shuffle    bool              `json:"shuffle"`
+	MusicDir   string            `json:"musicDir"` // Folder of the user's music files. Empty means the default folder.
+	Adaptive   bool              `json:"adaptive"` // Play the adaptive music instead of the playlist in the game
+	SynthSFX   bool              `json:"synthSFX"` // Play the synthesized sound effects instead of the recorded ones
+}
+
+var (
+	context     *audio.Context
+	current     = Settings{Volumes: [BusTotal]float64{1.0, 1.0, 1.0}}
+	musicPaused bool
+	musicNow    *Music
+	musics      []*Music
+	playlistNow *Playlist
+	errDisabled = errors.New("sound: audio is disabled")
+)
+
+// Init creates the audio context and loads the audio settings. If there is no usable audio device, an error is
+// returned and every function of this package turns into a no-op so the game can be played silently.
+func Init() error {
+	if err := settings.Load(settingsSection, &current); err != nil {
+		fmt.Println("sound: could not load the audio settings:", err)
+	}
+
+	context = audio.NewContext(SampleRate)
+
+	// Seek initializes the underlying device and reports its error without failing the whole game,
+	// unlike Play.
+	probe, err := context.NewPlayer(bytes.NewReader(make([]byte, 4)))
+	if err == nil {
+		err = probe.Rewind()
+		probe.Close()
+	}
+	if err != nil {
+		context = nil
+		return fmt.Errorf("sound: audio device is not available: %w", err)
+	}
+
+	return nil
+}
+
+// Enabled returns true if the audio device is available.
+func Enabled() bool {
+	return context != nil
+}
+
+// Context returns the audio context, or nil if the audio device is not available.
+func Context() *audio.Context {
+	return context
+}
+
+// Update progresses the music fades and the playlist. It must be called every tick.
+func Update() {
+	for iMusic := len(musics) - 1; iMusic >= 0; iMusic-- { // Backwards since a music can remove itself
+		musics[iMusic].update()
+	}
+	updateOneShots()
+
+	// Crossfade to the next track before the current one ends.
+	if (playlistNow != nil) && (musicNow != nil) && (musicNow.remaining() <= fadeDurationSec) {
+		playlistNow.Next()
+	}
+}
+
+// Volume returns the volume of the bus in the range [0, 1].
+func Volume(bus Bus) float64 {
+	return current.Volumes[bus]
+}
+
+// SetVolume changes the volume of the bus. The value is clamped to the range [0, 1].
+func SetVolume(bus Bus, volume float64) {
+	if volume < 0 {
+		volume = 0
+	} else if volume > 1 {
+		volume = 1
+	}
+	current.Volumes[bus] = volume
+}
+
+// StepVolume increases or decreases the volume of the bus by a fixed step.
+func StepVolume(bus Bus, up bool) {
+	step := volumeStep
+	if !up {
+		step = -step
+	}
+	// Round to avoid accumulating floating point errors.
+	SetVolume(bus, float64(int((Volume(bus)+step)*10+0.5))/10)
+}
+
+// busVolume returns the final volume multiplier of the bus.
+func busVolume(bus Bus) float64 {
+	return current.Volumes[BusMaster] * current.Volumes[bus]
+}
+
+// SaveSettings writes the audio settings to the settings file.
+func SaveSettings() error {
+	return settings.Save(settingsSection, &current)
+}
+
+// SFXMuted returns true if sound effects are turned off.
+func SFXMuted() bool {
+	return current.SFXMuted
+}
+
+// ToggleSFX turns the sound effects on or off.
+func ToggleSFX() {
+	current.SFXMuted = !current.SFXMuted
+	saveSettings()
+}
+
+// MusicMuted returns true if the music is turned off by the player.
+func MusicMuted() bool {
+	return current.MusicMuted
+}
+
+// ToggleMusic turns the music on or off.
+func ToggleMusic() {
+	current.MusicMuted = !current.MusicMuted
+	saveSettings()
+	refreshMusic()
+}
+
+// SetMusicPaused pauses or resumes the music along with the game.
+func SetMusicPaused(paused bool) {
+	musicPaused = paused
+	refreshMusic()
+}
+
+// PlayMusic fades the current music out and the given music in.
+func PlayMusic(music *Music) {
+	playlistNow = nil
+	crossfadeTo(music)
+}
+
+func crossfadeTo(music *Music) {
+	if music == musicNow {
+		return
+	}
+	if musicNow != nil {
+		if musicNow.track != nil {
+			musicNow.fadeOutAndClose()
+		} else {
+			musicNow.fadeTo(0)
+		}
+	}
+	musicNow = music
+	refreshMusic()
+}
+
+// SynthSFX returns true if the synthesized sound effects are preferred.
+func SynthSFX() bool {
+	return current.SynthSFX
+}
+
+// ToggleSynthSFX switches between the synthesized and the recorded sound effects.
+func ToggleSynthSFX() {
+	current.SynthSFX = !current.SynthSFX
+}
+
+// Adaptive returns true if the game plays the adaptive music instead of the playlist.
+func Adaptive() bool {
+	return current.Adaptive
+}
+
+// ToggleAdaptive turns the adaptive music on or off.
+func ToggleAdaptive() {
+	current.Adaptive = !current.Adaptive
+}
+
+// Shuffle returns true if the playlists are played in random order.
+func Shuffle() bool {
+	return current.Shuffle
+}
+
+// ToggleShuffle turns the shuffle mode of the playlists on or off.
+func ToggleShuffle() {
+	current.Shuffle = !current.Shuffle
+	if playlistNow != nil {
+		playlistNow.reorder()
+	}
+}
+
+// refreshMusic fades the current music in or out according to the pause and mute states.
+func refreshMusic() {
+	if musicNow == nil {
+		return
+	}
+	if musicPaused || current.MusicMuted {
+		musicNow.fadeTo(0)
+	} else {
+		musicNow.fadeTo(1)
+	}
+}
+
+func saveSettings() {
+	if err := SaveSettings(); err != nil {
+		fmt.Println("sound: could not save the audio settings:", err)
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package sound
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anilkonac/snake-ebiten/game/settings"
+	"github.com/hajimehoshi/ebiten/v2/audio/mp3"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+const (
+	userMusicDirName = "music"
+	bytesPerSecond   = SampleRate * 4 // 16 bit stereo
+)
+
+// Track is a music file that is decoded only when it is played.
+type Track struct {
+	Name     string
+	fileName string
+	volume   float64
+	read     func() ([]byte, error)
+}
+
+// NewTrack creates a track from an in-memory music file. The format is determined by the file extension.
+func NewTrack(fileName string, data []byte, volume float64) *Track {
+	return &Track{
+		Name:     trackName(fileName),
+		fileName: fileName,
+		volume:   volume,
+		read: func() ([]byte, error) {
+			return data, nil
+		},
+	}
+}
+
+// UserTracks returns the ogg, wav and mp3 files in the user's music folder.
+func UserTracks(volume float64) ([]*Track, error) {
+	dir := current.MusicDir
+	if dir == "" {
+		settingsDir, err := settings.Dir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(settingsDir, userMusicDirName)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []*Track
+	for _, entry := range entries {
+		if entry.IsDir() || !isSupported(entry.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		tracks = append(tracks, &Track{
+			Name:     trackName(entry.Name()),
+			fileName: entry.Name(),
+			volume:   volume,
+			read: func() ([]byte, error) {
+				return os.ReadFile(path)
+			},
+		})
+	}
+	return tracks, nil
+}
+
+func trackName(fileName string) string {
+	base := filepath.Base(fileName)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+func isSupported(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ogg", ".wav", ".mp3":
+		return true
+	}
+	return false
+}
+
+// decode returns the PCM stream of the track and its length in bytes.
+func (t *Track) decode() (io.ReadSeeker, int64, error) {
+	data, err := t.read()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	src := bytes.NewReader(data)
+	switch strings.ToLower(filepath.Ext(t.fileName)) {
+	case ".ogg":
+		stream, err := vorbis.DecodeWithSampleRate(SampleRate, src)
+		if err != nil {
+			return nil, 0, err
+		}
+		return stream, stream.Length(), nil
+	case ".wav":
+		stream, err := wav.DecodeWithSampleRate(SampleRate, src)
+		if err != nil {
+			return nil, 0, err
+		}
+		return stream, stream.Length(), nil
+	case ".mp3":
+		stream, err := mp3.DecodeWithSampleRate(SampleRate, src)
+		if err != nil {
+			return nil, 0, err
+		}
+		return stream, stream.Length(), nil
+	}
+	return nil, 0, fmt.Errorf("sound: unsupported music format: %s", t.fileName)
+}
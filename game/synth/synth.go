@@ -0,0 +1,199 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package synth generates sound effects at runtime with oscillators, envelopes, noise and pitch sweeps.
+package synth
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// Waveform is the shape of an oscillator.
+type Waveform uint8
+
+const (
+	WaveSine Waveform = iota
+	WaveSquare
+	WaveTriangle
+	WaveSaw
+	WaveNoise
+)
+
+// Envelope shapes the amplitude of a tone over time. Times are in seconds, Sustain is a level in [0, 1].
+type Envelope struct {
+	Attack  float64
+	Decay   float64
+	Sustain float64
+	Release float64
+}
+
+// Tone is a single oscillator whose pitch sweeps exponentially from FreqStart to FreqEnd in Duration seconds.
+// The release of the envelope starts after Duration.
+type Tone struct {
+	Waveform  Waveform
+	FreqStart float64 // [Hz]
+	FreqEnd   float64 // [Hz], equal to FreqStart for a constant pitch
+	Duration  float64 // [s]
+	Delay     float64 // [s] before the tone starts
+	Envelope  Envelope
+	Volume    float64
+}
+
+// Patch is a sound effect made of tones played together.
+type Patch []Tone
+
+// Stream renders a patch as 16 bit stereo PCM. It implements io.Reader and can be played with
+// audio.Context.NewPlayer.
+type Stream struct {
+	patch      Patch
+	sampleRate float64
+	frame      int
+	phases     []float64
+	noise      uint32
+	noiseValue []float64
+}
+
+const bytesPerFrame = 4
+
+func NewStream(patch Patch, sampleRate int) *Stream {
+	return &Stream{
+		patch:      patch,
+		sampleRate: float64(sampleRate),
+		phases:     make([]float64, len(patch)),
+		noiseValue: make([]float64, len(patch)),
+		noise:      0x9E3779B9,
+	}
+}
+
+// Render returns the whole patch as 16 bit stereo PCM.
+func Render(patch Patch, sampleRate int) []byte {
+	pcm, _ := io.ReadAll(NewStream(patch, sampleRate))
+	return pcm
+}
+
+// Length returns the duration of the patch in seconds including the delays and releases.
+func (p Patch) Length() float64 {
+	var length float64
+	for _, tone := range p {
+		length = math.Max(length, tone.Delay+tone.Duration+tone.Envelope.Release)
+	}
+	return length
+}
+
+func (s *Stream) Read(buf []byte) (int, error) {
+	totalFrames := int(s.patch.Length() * s.sampleRate)
+	if s.frame >= totalFrames {
+		return 0, io.EOF
+	}
+
+	numFrames := len(buf) / bytesPerFrame
+	if remaining := totalFrames - s.frame; numFrames > remaining {
+		numFrames = remaining
+	}
+
+	for iFrame := 0; iFrame < numFrames; iFrame++ {
+		t := float64(s.frame) / s.sampleRate
+		var sample float64
+		for iTone := range s.patch {
+			sample += s.toneSample(iTone, t)
+		}
+		sample = math.Max(-1, math.Min(sample, 1))
+
+		value := uint16(int16(sample * math.MaxInt16))
+		binary.LittleEndian.PutUint16(buf[iFrame*bytesPerFrame:], value)
+		binary.LittleEndian.PutUint16(buf[iFrame*bytesPerFrame+2:], value)
+		s.frame++
+	}
+
+	return numFrames * bytesPerFrame, nil
+}
+
+func (s *Stream) toneSample(iTone int, t float64) float64 {
+	tone := &s.patch[iTone]
+	t -= tone.Delay
+	if (t < 0) || (t > tone.Duration+tone.Envelope.Release) {
+		return 0
+	}
+
+	// Sweep the pitch exponentially so that it sounds linear.
+	freq := tone.FreqStart
+	if (tone.FreqEnd > 0) && (tone.FreqEnd != tone.FreqStart) && (tone.Duration > 0) {
+		progress := math.Min(t/tone.Duration, 1)
+		freq = tone.FreqStart * math.Pow(tone.FreqEnd/tone.FreqStart, progress)
+	}
+
+	lastPhase := s.phases[iTone]
+	s.phases[iTone] = math.Mod(lastPhase+freq/s.sampleRate, 1)
+	phase := s.phases[iTone]
+
+	var value float64
+	switch tone.Waveform {
+	case WaveSine:
+		value = math.Sin(2 * math.Pi * phase)
+	case WaveSquare:
+		value = 1
+		if phase >= 0.5 {
+			value = -1
+		}
+	case WaveTriangle:
+		value = 4*math.Abs(phase-0.5) - 1
+	case WaveSaw:
+		value = 2*phase - 1
+	case WaveNoise:
+		// Sample and hold the noise at the tone frequency so that the sweep changes its color.
+		if phase < lastPhase {
+			s.noiseValue[iTone] = s.nextNoise()
+		}
+		value = s.noiseValue[iTone]
+	}
+
+	return value * tone.Volume * tone.Envelope.level(t, tone.Duration)
+}
+
+// nextNoise returns a pseudo random value in [-1, 1] using xorshift.
+func (s *Stream) nextNoise() float64 {
+	s.noise ^= s.noise << 13
+	s.noise ^= s.noise >> 17
+	s.noise ^= s.noise << 5
+	return float64(s.noise)/math.MaxUint32*2 - 1
+}
+
+// level returns the amplitude of the envelope at time t for a note held for duration seconds.
+func (e Envelope) level(t, duration float64) float64 {
+	if t > duration {
+		if e.Release <= 0 {
+			return 0
+		}
+		return e.heldLevel(duration) * math.Max(0, 1-(t-duration)/e.Release)
+	}
+	return e.heldLevel(t)
+}
+
+func (e Envelope) heldLevel(t float64) float64 {
+	if t < e.Attack {
+		return t / e.Attack
+	}
+	t -= e.Attack
+	if t < e.Decay {
+		return 1 - (1-e.Sustain)*t/e.Decay
+	}
+	return e.Sustain
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"math"
+
+	"github.com/anilkonac/snake-ebiten/game/sound"
+	"github.com/anilkonac/snake-ebiten/game/synth"
+)
+
+// The synthesized sound effects are the eating and hit sounds. The game has no power-ups, so there are no
+// power-up patches; a pickup added later gets its own patch here next to them.
+
+// Synthesized sound effect parameters
+const (
+	synthEatingFreqBase  = 440.0 // [Hz]
+	synthEatingMaxSteps  = 24    // Semitones the eating sound can rise
+	synthEatingVolume    = 0.5
+	synthHitVolume       = 0.9
+	semitonesInAnOctave  = 12.0
+	synthEatingSweepMult = 1.5
+)
+
+var patchHit = synth.Patch{
+	{ // Crunch
+		Waveform:  synth.WaveNoise,
+		FreqStart: 9000,
+		FreqEnd:   1500,
+		Duration:  0.18,
+		Envelope:  synth.Envelope{Attack: 0.002, Decay: 0.12, Sustain: 0.2, Release: 0.15},
+		Volume:    0.5,
+	},
+	{ // Thump
+		Waveform:  synth.WaveSine,
+		FreqStart: 180,
+		FreqEnd:   40,
+		Duration:  0.25,
+		Envelope:  synth.Envelope{Attack: 0.005, Decay: 0.2, Sustain: 0.5, Release: 0.2},
+		Volume:    0.8,
+	},
+}
+
+// patchEating returns a blip whose pitch rises a semitone with each step.
+func patchEating(step int) synth.Patch {
+	if step > synthEatingMaxSteps {
+		step = synthEatingMaxSteps
+	}
+	freq := synthEatingFreqBase * math.Pow(2, float64(step)/semitonesInAnOctave)
+
+	return synth.Patch{
+		{
+			Waveform:  synth.WaveSquare,
+			FreqStart: freq,
+			FreqEnd:   freq * synthEatingSweepMult,
+			Duration:  0.06,
+			Envelope:  synth.Envelope{Attack: 0.003, Decay: 0.05, Sustain: 0.6, Release: 0.05},
+			Volume:    0.35,
+		},
+		{
+			Waveform:  synth.WaveTriangle,
+			FreqStart: freq * 2,
+			FreqEnd:   freq * 2 * synthEatingSweepMult,
+			Duration:  0.05,
+			Delay:     0.05,
+			Envelope:  synth.Envelope{Attack: 0.003, Decay: 0.04, Sustain: 0.5, Release: 0.06},
+			Volume:    0.4,
+		},
+	}
+}
+
+func playSynthEating(step int) {
+	sound.PlayStream(synth.NewStream(patchEating(step), sound.SampleRate), synthEatingVolume)
+}
+
+func playSynthHit() {
+	sound.PlayStream(synth.NewStream(patchHit, sound.SampleRate), synthHitVolume)
+}
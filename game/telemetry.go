@@ -0,0 +1,98 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"path"
+
+	s "github.com/anilkonac/snake-ebiten/game/object/snake"
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/telemetry"
+)
+
+// The gameplay events of a session are logged to a JSONL file, see cmd/telemetry for their analysis.
+// The log is written only if the platform keeps user files, see telemetryLogEnabled.
+var (
+	telemetrySink *telemetry.JSONLSink
+	gamesStarted  int // Games started in the session
+)
+
+func init() {
+	if telemetryLogEnabled {
+		telemetrySink = telemetry.NewJSONLSink(path.Join(telemetry.Dir, telemetry.Session+".jsonl"))
+		telemetry.Subscribe(telemetrySink.Handle)
+	}
+}
+
+// startTelemetry numbers the game and publishes its start.
+func (g *gameScene) startTelemetry() {
+	gamesStarted++
+	g.gameNumber = gamesStarted
+	g.publishEvent(telemetry.Event{
+		Kind:    telemetry.KindGameStart,
+		World:   &telemetry.Size{Width: param.WorldWidth, Height: param.WorldHeight},
+		SimRate: param.SimRate,
+		Mode:    g.mode.id,
+	})
+}
+
+// publishEvent publishes the event with the tick and the snake state of the game.
+func (g *gameScene) publishEvent(event telemetry.Event) {
+	event.Game = g.gameNumber
+	event.Tick = g.ticks
+	event.Snake = snakeState(g.snake)
+	telemetry.Publish(event)
+}
+
+func snakeState(snake *s.Snake) telemetry.SnakeState {
+	var numUnits int
+	for unit := snake.UnitHead; unit != nil; unit = unit.Next {
+		numUnits++
+	}
+
+	return telemetry.SnakeState{
+		Head:      telemetry.Vec{X: snake.UnitHead.HeadCenter.X, Y: snake.UnitHead.HeadCenter.Y},
+		Direction: snake.UnitHead.Direction.String(),
+		Length:    snake.Length(),
+		Speed:     snake.Speed,
+		Units:     numUnits,
+		FoodEaten: int(snake.FoodEaten),
+	}
+}
+
+// publishMute publishes the mute state of the sound channel after it is toggled.
+func (g *gameScene) publishMute(channel string, muted bool) {
+	kind := telemetry.KindUnmute
+	if muted {
+		kind = telemetry.KindMute
+	}
+	g.publishEvent(telemetry.Event{Kind: kind, Channel: channel})
+}
+
+// flushTelemetry writes the events logged so far.
+func flushTelemetry() {
+	if telemetrySink == nil {
+		return
+	}
+	if _, err := telemetrySink.Flush(); err != nil {
+		fmt.Println("Could not save the telemetry log:", err)
+	}
+}
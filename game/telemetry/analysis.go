@@ -0,0 +1,186 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package telemetry
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Death causes by the part of the snake its head has hit
+const (
+	CauseNeck    = "neck" // The unit right behind the head, usually a too quick turn back
+	CauseBody    = "body"
+	CauseTail    = "tail"
+	CauseUnknown = "unknown" // The game is over without a collision event
+)
+
+// Summary is the result of the analysis of the events.
+type Summary struct {
+	Sessions        int
+	Games           int // Finished games
+	AverageScore    float64
+	BestScore       int
+	AverageTime     float64 // Seconds alive
+	DeathCauses     map[string]int
+	DeathsNearEdges int // Collisions within the edge distance of a world edge
+	EdgeDistance    float64
+}
+
+// gameKey identifies a game in the events of all sessions.
+type gameKey struct {
+	session string
+	game    int
+}
+
+// gameInfo holds the events of a game that are needed for its death.
+type gameInfo struct {
+	world     Size
+	simRate   int
+	collision *Event
+}
+
+// ReadEvents decodes the JSON lines of a telemetry log.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for numLine := 1; scanner.Scan(); numLine++ {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return events, fmt.Errorf("line %d: %w", numLine, err)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Analyze summarizes the finished games in the events. A death is near an edge if the collision point is
+// closer than edgeDistance to one of the world edges, where the snake teleports to the other side.
+func Analyze(events []Event, edgeDistance float64) Summary {
+	summary := Summary{DeathCauses: make(map[string]int), EdgeDistance: edgeDistance}
+	sessions := make(map[string]bool)
+	games := make(map[gameKey]*gameInfo)
+	var sumScore, sumTime float64
+
+	for iEvent := range events {
+		event := &events[iEvent]
+		sessions[event.Session] = true
+		key := gameKey{event.Session, event.Game}
+		game, ok := games[key]
+		if !ok {
+			game = &gameInfo{}
+			games[key] = game
+		}
+
+		switch event.Kind {
+		case KindGameStart:
+			if event.World != nil {
+				game.world = *event.World
+			}
+			game.simRate = event.SimRate
+		case KindCollision:
+			game.collision = event
+		case KindGameOver:
+			summary.Games++
+			sumScore += float64(event.Score)
+			if event.Score > summary.BestScore {
+				summary.BestScore = event.Score
+			}
+			if game.simRate > 0 {
+				sumTime += float64(event.Tick) / float64(game.simRate)
+			}
+
+			summary.DeathCauses[deathCause(game.collision)]++
+			if (game.collision != nil) && nearEdge(game.collision.Pos, game.world, edgeDistance) {
+				summary.DeathsNearEdges++
+			}
+		}
+	}
+
+	summary.Sessions = len(sessions)
+	if summary.Games > 0 {
+		summary.AverageScore = sumScore / float64(summary.Games)
+		summary.AverageTime = sumTime / float64(summary.Games)
+	}
+	return summary
+}
+
+func deathCause(collision *Event) string {
+	switch {
+	case collision == nil:
+		return CauseUnknown
+	case collision.UnitIndex <= 1:
+		return CauseNeck
+	case collision.UnitIndex >= collision.Snake.Units-1:
+		return CauseTail
+	default:
+		return CauseBody
+	}
+}
+
+func nearEdge(pos *Vec, world Size, distance float64) bool {
+	if (pos == nil) || (world.Width <= 0) || (world.Height <= 0) {
+		return false
+	}
+	toEdgeX := math.Min(pos.X, float64(world.Width)-pos.X)
+	toEdgeY := math.Min(pos.Y, float64(world.Height)-pos.Y)
+	return math.Min(toEdgeX, toEdgeY) < distance
+}
+
+// String formats the summary as a report.
+func (s Summary) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Sessions: %d\n", s.Sessions)
+	fmt.Fprintf(&builder, "Games: %d\n", s.Games)
+	fmt.Fprintf(&builder, "Average score: %.1f\n", s.AverageScore)
+	fmt.Fprintf(&builder, "Best score: %d\n", s.BestScore)
+	fmt.Fprintf(&builder, "Average time alive: %.1f s\n", s.AverageTime)
+
+	builder.WriteString("Death causes:\n")
+	causes := make([]string, 0, len(s.DeathCauses))
+	for cause := range s.DeathCauses {
+		causes = append(causes, cause)
+	}
+	sort.Strings(causes)
+	for _, cause := range causes {
+		fmt.Fprintf(&builder, "  %-8s %d (%.0f%%)\n", cause, s.DeathCauses[cause], percent(s.DeathCauses[cause], s.Games))
+	}
+
+	fmt.Fprintf(&builder, "Deaths within %.0f px of an edge: %d (%.0f%%)\n",
+		s.EdgeDistance, s.DeathsNearEdges, percent(s.DeathsNearEdges, s.Games))
+	return builder.String()
+}
+
+func percent(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(count) / float64(total)
+}
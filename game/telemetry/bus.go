@@ -0,0 +1,46 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package telemetry
+
+import "time"
+
+// Handler receives the published events.
+type Handler func(Event)
+
+var (
+	// Session identifies the run of the game the events are published in.
+	Session  = time.Now().Format("2006-01-02_15-04-05")
+	handlers []Handler
+)
+
+// Subscribe adds the handler to the receivers of the events. The handlers are called synchronously by
+// Publish, so they should be quick.
+func Subscribe(handler Handler) {
+	handlers = append(handlers, handler)
+}
+
+// Publish stamps the event with the session and sends it to the subscribed handlers in the order they
+// are subscribed.
+func Publish(event Event) {
+	event.Session = Session
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
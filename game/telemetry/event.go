@@ -0,0 +1,83 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package telemetry publishes the gameplay events to the subscribed sinks and summarizes the logged events.
+// It does not depend on the engine, so the logs can be analyzed without a display.
+package telemetry
+
+// Kind is the type of a gameplay event.
+type Kind string
+
+const (
+	KindGameStart   Kind = "gameStart"
+	KindTurn        Kind = "turn"        // The turn is taken immediately
+	KindTurnQueued  Kind = "turnQueued"  // The turn is queued until it is safe
+	KindTurnIgnored Kind = "turnIgnored" // The turn is pressed too early to be queued
+	KindFoodSpawn   Kind = "foodSpawn"
+	KindFoodRespawn Kind = "foodRespawn" // The food has spawned on the snake and is moved
+	KindFoodEat     Kind = "foodEat"
+	KindWrap        Kind = "wrap" // The head has teleported to the other side of the world
+	KindCollision   Kind = "collision"
+	KindPause       Kind = "pause"
+	KindResume      Kind = "resume"
+	KindMute        Kind = "mute"
+	KindUnmute      Kind = "unmute"
+	KindGameOver    Kind = "gameOver"
+	KindCheat       Kind = "cheat" // A console command has changed the game, it is not recorded
+)
+
+// Event is a gameplay event with the state of the snake at its tick. The fields after the snake state are
+// set only by the kinds they are used in.
+type Event struct {
+	Kind    Kind       `json:"kind"`
+	Session string     `json:"session"` // Set by Publish
+	Game    int        `json:"game"`    // Number of the game in the session, starting from 1
+	Tick    int        `json:"tick"`    // Ticks played in the game
+	Snake   SnakeState `json:"snake"`
+
+	World     *Size  `json:"world,omitempty"`     // Game start
+	SimRate   int    `json:"simRate,omitempty"`   // Game start, ticks per second
+	Mode      string `json:"mode,omitempty"`      // Game start
+	Direction string `json:"direction,omitempty"` // Turns
+	Pos       *Vec   `json:"pos,omitempty"`       // Food events and the collision point
+	UnitIndex int    `json:"unitIndex,omitempty"` // Collision, index of the unit hit counted from the head
+	Channel   string `json:"channel,omitempty"`   // Mute events, music or sfx
+	Score     int    `json:"score,omitempty"`     // Food eaten and game over
+	Command   string `json:"command,omitempty"`   // Cheat, name of the console command
+}
+
+// SnakeState is the state of the player's snake attached to every event.
+type SnakeState struct {
+	Head      Vec     `json:"head"`
+	Direction string  `json:"direction"`
+	Length    float64 `json:"length"`
+	Speed     float64 `json:"speed"`
+	Units     int     `json:"units"`
+	FoodEaten int     `json:"foodEaten"`
+}
+
+type Vec struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type Size struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/anilkonac/snake-ebiten/game/settings"
+)
+
+// Dir is the directory of the telemetry logs next to the settings file.
+const Dir = "telemetry"
+
+// JSONLSink collects the events as JSON lines and appends them to a user file on each flush. Only the
+// events since the last flush are kept in memory.
+type JSONLSink struct {
+	name    string
+	buf     bytes.Buffer
+	encoder *json.Encoder
+}
+
+// NewJSONLSink creates a sink writing to the name, a slash separated path next to the settings file.
+func NewJSONLSink(name string) *JSONLSink {
+	sink := &JSONLSink{name: name}
+	sink.encoder = json.NewEncoder(&sink.buf)
+	return sink
+}
+
+// Handle encodes the event as a JSON line. It is the handler of the sink to subscribe to the events.
+func (s *JSONLSink) Handle(event Event) {
+	// An event has no values that cannot be encoded.
+	_ = s.encoder.Encode(event)
+}
+
+// Flush appends the events collected since the last flush to the file and returns its location. The
+// events are kept if they could not be written, so they are retried with the next flush.
+func (s *JSONLSink) Flush() (string, error) {
+	location, err := settings.AppendUserFile(s.name, s.buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+	s.buf.Reset()
+	return location, nil
+}
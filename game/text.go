@@ -6,6 +6,7 @@ import (
 
 	"github.com/anilkonac/snake-ebiten/game/object"
 	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/theme"
 	res "github.com/anilkonac/snake-ebiten/resource"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
@@ -19,6 +20,7 @@ const (
 	fontSizeScore   = 32
 	fontSizeDebug   = 20
 	fontSizeTitle   = 128
+	fontSizeMenu    = 26
 	scoreTextShiftX = 10
 	scoreTextShiftY = 8
 	fpsTextShiftX   = 0
@@ -28,65 +30,82 @@ const (
 var (
 	fontFaceDebug      font.Face
 	fontFaceTitle      font.Face
+	fontFaceMenu       font.Face
 	boundTextScore     image.Rectangle
 	boundTextFPS       image.Rectangle
 	boundTextTitle     image.Rectangle
 	boundTextKeyPrompt image.Rectangle
 )
 
+var fontPaths = map[string]string{
+	theme.FontRounded: res.PathFontRounded,
+	theme.FontVT323:   res.PathFontDebug,
+}
+
 func init() {
-	var err error
-	var bytesFontRounded, bytesFontDebug []byte
-	var tt *sfnt.Font
+	loadFonts(theme.Current().Fonts)
+}
 
-	// Read Font files
-	bytesFontRounded, err = res.FS.ReadFile(res.PathFontRounded)
-	panicErr(err)
-	bytesFontDebug, err = res.FS.ReadFile(res.PathFontDebug)
-	panicErr(err)
+// loadFonts creates the font faces of the theme fonts. Unknown font names fall back to the default fonts.
+func loadFonts(fonts theme.Fonts) {
+	fontText := parseFont(fonts.Text, theme.FontRounded)
+	fontDebug := parseFont(fonts.Debug, theme.FontVT323)
 
-	tt, err = opentype.Parse(bytesFontRounded)
-	panicErr(err)
+	param.FontFaceScore = newFace(fontText, fontSizeScore)
+	fontFaceTitle = newFace(fontText, fontSizeTitle)
+	fontFaceMenu = newFace(fontText, fontSizeMenu)
+	fontFaceDebug = newFace(fontDebug, fontSizeDebug)
 
-	param.FontFaceScore, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    fontSizeScore,
-		DPI:     dpi,
-		Hinting: font.HintingFull,
-	})
-	panicErr(err)
+	boundTextScore = text.BoundString(param.FontFaceScore, "Score: 55555")
+	boundTextTitle = text.BoundString(fontFaceTitle, textTitle)
+	boundTextKeyPrompt = text.BoundString(param.FontFaceScore, textPressToPlay)
+	boundTextFPS = text.BoundString(fontFaceDebug, "TPS: 60.0\tFPS: 5555.5")
 
-	fontFaceTitle, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    fontSizeTitle,
-		DPI:     dpi,
-		Hinting: font.HintingFull,
-	})
+	object.InitScoreAnim()
+}
+
+func parseFont(name, fallback string) *sfnt.Font {
+	path, ok := fontPaths[name]
+	if !ok {
+		path = fontPaths[fallback]
+	}
+
+	bytesFont, err := res.FS.ReadFile(path)
 	panicErr(err)
 
-	tt, err = opentype.Parse(bytesFontDebug)
+	tt, err := opentype.Parse(bytesFont)
 	panicErr(err)
 
-	fontFaceDebug, err = opentype.NewFace(tt, &opentype.FaceOptions{
-		Size:    fontSizeDebug,
+	return tt
+}
+
+func newFace(tt *sfnt.Font, size float64) font.Face {
+	face, err := opentype.NewFace(tt, &opentype.FaceOptions{
+		Size:    size,
 		DPI:     dpi,
 		Hinting: font.HintingFull,
 	})
 	panicErr(err)
 
-	boundTextScore = text.BoundString(param.FontFaceScore, "Score: 55555")
-	boundTextTitle = text.BoundString(fontFaceTitle, textTitle)
-	boundTextKeyPrompt = text.BoundString(param.FontFaceScore, textPressToPlay)
-	boundTextFPS = text.BoundString(fontFaceDebug, "TPS: 60.0\tFPS: 5555.5")
-
-	object.InitScoreAnim()
+	return face
 }
 
 func drawFPS(screen *ebiten.Image) {
 	if param.PrintFPS {
-		msg := fmt.Sprintf("TPS: %.1f\tFPS: %.1f", ebiten.ActualTPS(), ebiten.ActualFPS())
+		msg := fmt.Sprintf("TPS: %.1f\tFPS: %.1f", clock.actualTPS(), ebiten.ActualFPS())
 		text.Draw(screen, msg, fontFaceDebug, param.ScreenWidth-boundTextFPS.Size().X-fpsTextShiftX, -boundTextFPS.Min.Y+fpsTextShiftY, param.ColorDebug)
 	}
 }
 
+// drawTimeScale shows the time scale of the game below the TPS/FPS line when it is changed.
+func drawTimeScale(screen *ebiten.Image) {
+	if param.TimeScale != 1 {
+		msg := fmt.Sprintf("Time: %.2gx", param.TimeScale)
+		lineY := -boundTextFPS.Min.Y + 2*fpsTextShiftY + boundTextFPS.Size().Y
+		text.Draw(screen, msg, fontFaceDebug, param.ScreenWidth-boundTextFPS.Size().X-fpsTextShiftX, lineY, param.ColorDebug)
+	}
+}
+
 func panicErr(err error) {
 	if err != nil {
 		panic(err)
@@ -0,0 +1,87 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package theme
+
+func rgb(r, g, b uint8) Color {
+	return Color{r, g, b, 255}
+}
+
+func builtinThemes() []Theme {
+	return []Theme{
+		{
+			// Palette: https://coolors.co/palette/003049-d62828-f77f00-fcbf49-eae2b7
+			Name: "Dark",
+			Palette: Palette{
+				Background: rgb(0, 48, 73),     // ~ Prussian Blue
+				Snake1:     rgb(252, 191, 73),  // ~ Maximum Yellow Red
+				Snake2:     rgb(247, 127, 0),   // ~ Orange
+				Food:       rgb(214, 40, 40),   // ~ Maximum Red
+				Debug:      rgb(234, 226, 183), // ~ Lemon Meringue
+				Score:      rgb(247, 127, 0),   // ~ Orange
+			},
+			Fonts:        Fonts{Text: FontRounded, Debug: FontVT323},
+			CornerRadius: 15,
+			Background:   BackgroundFlat,
+		},
+		{
+			Name: "Light",
+			Palette: Palette{
+				Background: rgb(234, 226, 183), // ~ Lemon Meringue
+				Snake1:     rgb(0, 48, 73),     // ~ Prussian Blue
+				Snake2:     rgb(38, 102, 140),  // ~ Lapis Lazuli
+				Food:       rgb(214, 40, 40),   // ~ Maximum Red
+				Debug:      rgb(0, 48, 73),     // ~ Prussian Blue
+				Score:      rgb(214, 40, 40),   // ~ Maximum Red
+			},
+			Fonts:        Fonts{Text: FontRounded, Debug: FontVT323},
+			CornerRadius: 15,
+			Background:   BackgroundGradient,
+		},
+		{
+			Name: "High Contrast",
+			Palette: Palette{
+				Background: rgb(0, 0, 0),
+				Snake1:     rgb(255, 255, 255),
+				Snake2:     rgb(255, 230, 0),
+				Food:       rgb(0, 229, 255),
+				Debug:      rgb(255, 255, 255),
+				Score:      rgb(255, 230, 0),
+			},
+			Fonts:        Fonts{Text: FontVT323, Debug: FontVT323},
+			CornerRadius: 0,
+			Background:   BackgroundFlat,
+		},
+		{
+			// Okabe-Ito palette, distinguishable with deuteranopia and protanopia.
+			Name: "Colorblind Safe",
+			Palette: Palette{
+				Background: rgb(20, 24, 40),
+				Snake1:     rgb(230, 159, 0),   // Orange
+				Snake2:     rgb(240, 228, 66),  // Yellow
+				Food:       rgb(86, 180, 233),  // Sky Blue
+				Debug:      rgb(255, 255, 255), // White
+				Score:      rgb(240, 228, 66),  // Yellow
+			},
+			Fonts:        Fonts{Text: FontRounded, Debug: FontVT323},
+			CornerRadius: 15,
+			Background:   BackgroundFlat,
+		},
+	}
+}
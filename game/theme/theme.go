@@ -0,0 +1,194 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package theme holds the visual themes of the game: the palette, the fonts, the corner radii and the
+// background shader. Applying a theme updates the color variables of the param package in place, so every
+// object referencing them is updated live.
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anilkonac/snake-ebiten/game/param"
+	"github.com/anilkonac/snake-ebiten/game/settings"
+)
+
+const (
+	settingsSection = "theme"
+	userThemesDir   = "themes"
+)
+
+// Font names
+const (
+	FontRounded = "rounded"
+	FontVT323   = "vt323"
+)
+
+// Background shader names
+const (
+	BackgroundFlat     = "flat"
+	BackgroundGradient = "gradient"
+)
+
+type Palette struct {
+	Background Color `json:"background"`
+	Snake1     Color `json:"snake1"`
+	Snake2     Color `json:"snake2"`
+	Food       Color `json:"food"`
+	Debug      Color `json:"debug"`
+	Score      Color `json:"score"`
+}
+
+type Fonts struct {
+	Text  string `json:"text"`  // Score, title and menu texts
+	Debug string `json:"debug"` // FPS, help and debug texts
+}
+
+type Theme struct {
+	Name         string  `json:"name"`
+	Palette      Palette `json:"palette"`
+	Fonts        Fonts   `json:"fonts"`
+	CornerRadius float32 `json:"cornerRadius"`
+	Background   string  `json:"background"` // Background shader
+}
+
+type savedSettings struct {
+	Name string `json:"name"`
+}
+
+var (
+	themes  = builtinThemes()
+	current = 0
+)
+
+func init() {
+	userThemes, err := loadUserThemes()
+	if err != nil {
+		fmt.Println("theme: could not load the user themes:", err)
+	}
+	themes = append(themes, userThemes...)
+
+	var saved savedSettings
+	if err := settings.Load(settingsSection, &saved); err != nil {
+		fmt.Println("theme: could not load the theme settings:", err)
+	}
+	for iTheme := range themes {
+		if themes[iTheme].Name == saved.Name {
+			current = iTheme
+		}
+	}
+	themes[current].apply()
+}
+
+// Current returns the theme in use.
+func Current() *Theme {
+	return &themes[current]
+}
+
+// Select applies the theme with the given offset from the current one in the theme list and saves the choice.
+func Select(offset int) *Theme {
+	current = ((current+offset)%len(themes) + len(themes)) % len(themes)
+	themes[current].apply()
+
+	if err := settings.Save(settingsSection, savedSettings{Name: themes[current].Name}); err != nil {
+		fmt.Println("theme: could not save the theme settings:", err)
+	}
+	return &themes[current]
+}
+
+func (t *Theme) apply() {
+	param.ColorBackground = color.RGBA(t.Palette.Background)
+	param.ColorSnake1 = color.RGBA(t.Palette.Snake1)
+	param.ColorSnake2 = color.RGBA(t.Palette.Snake2)
+	param.ColorFood = color.RGBA(t.Palette.Food)
+	param.ColorDebug = color.RGBA(t.Palette.Debug)
+	param.ColorScore = color.RGBA(t.Palette.Score)
+	param.CornerRadius = t.CornerRadius
+	param.ThemeVersion++
+}
+
+// loadUserThemes reads the json theme files in the user's themes folder.
+// Missing fields are taken from the default theme.
+func loadUserThemes() ([]Theme, error) {
+	dir, err := settings.Dir()
+	if err != nil {
+		return nil, nil // No user folder on this platform
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, userThemesDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var userThemes []Theme
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, userThemesDir, entry.Name()))
+		if err != nil {
+			return userThemes, err
+		}
+
+		theme := themes[0]
+		if err = json.Unmarshal(data, &theme); err != nil {
+			return userThemes, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if theme.Name == themes[0].Name {
+			theme.Name = strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		}
+		userThemes = append(userThemes, theme)
+	}
+	return userThemes, nil
+}
+
+// Color is a color.RGBA that is written as a "#rrggbb" or "#rrggbbaa" string in json files.
+type Color color.RGBA
+
+func (c Color) MarshalText() ([]byte, error) {
+	if c.A == 255 {
+		return []byte(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)), nil
+	}
+	return []byte(fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)), nil
+}
+
+func (c *Color) UnmarshalText(text []byte) error {
+	str := string(text)
+	c.A = 255
+
+	var err error
+	switch len(str) {
+	case 7:
+		_, err = fmt.Sscanf(str, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(str, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = fmt.Errorf("theme: invalid color %q", str)
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2022 Anıl Konaç
+
+This file is part of snake-ebiten.
+
+snake-ebiten is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+snake-ebiten is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anilkonac/snake-ebiten/game/input"
+	"github.com/anilkonac/snake-ebiten/game/param"
+)
+
+// Ebitengine calls Update once per frame. The sim clock accumulates the wall clock time of the frames and
+// takes as many fixed ticks as it covers, so the game keeps its speed when the frame rate is low or differs
+// from the sim rate. The frames are drawn in between the last two simulation steps at the position given
+// by the sim clock.
+
+// Simulation rate presets in ticks per second
+var simRates = []int{60, 120, 240}
+
+// Sim clock parameters
+const simFrameRateMin = 15 // The game slows down below this frame rate, the time of longer frames is dropped
+
+// simClock measures the wall clock time that has not been simulated yet.
+type simClock struct {
+	last        time.Time     // Wall clock time of the last frame
+	accumulated time.Duration // Wall clock time that is ahead of the last tick
+	tpsStart    time.Time     // Start of the current measurement of the tick rate
+	tpsTicks    int           // Ticks taken since the start of the measurement
+	tps         float64       // Ticks taken in the last second
+}
+
+var clock simClock
+
+func (c *simClock) reset() {
+	c.last = time.Now()
+	c.accumulated = 0
+}
+
+// advance adds the wall clock time since the last frame and returns the number of ticks to take in this
+// frame. The ticks are clamped, so a slow device or a game that has been in the background does not have
+// to catch up. A frame without a tick still takes one if a key or button is pressed, so the press is not
+// lost, and the later frames make up for it.
+func (c *simClock) advance() int {
+	now := time.Now()
+	c.accumulated += now.Sub(c.last)
+	c.last = now
+
+	tickDuration := simTickDuration()
+	numTicks := int(c.accumulated / tickDuration)
+	if ticksMax := simTicksMax(); numTicks > ticksMax {
+		numTicks = ticksMax
+		c.accumulated = c.accumulated%tickDuration + time.Duration(ticksMax)*tickDuration
+	}
+	if (numTicks == 0) && input.AnyJustPressed() {
+		numTicks = 1
+	}
+	c.accumulated -= time.Duration(numTicks) * tickDuration
+
+	c.measureTPS(now, numTicks)
+	return numTicks
+}
+
+// step is called at the start of every tick.
+func (c *simClock) step() {
+	param.SimTicks++
+}
+
+// alpha returns the position of the frame between the last two simulation steps. The frames are drawn
+// one tick behind the wall clock, so the positions can be interpolated instead of extrapolated.
+func (c *simClock) alpha() float64 {
+	alpha := float64(c.accumulated+time.Since(c.last)) / float64(simTickDuration())
+	if alpha < 0 {
+		return 0
+	} else if alpha > 1 {
+		return 1
+	}
+	return alpha
+}
+
+// measureTPS counts the ticks of every second, ebiten.ActualTPS is the frame rate since Update is called
+// once per frame.
+func (c *simClock) measureTPS(now time.Time, numTicks int) {
+	c.tpsTicks += numTicks
+	if elapsed := now.Sub(c.tpsStart); elapsed >= time.Second {
+		c.tps = float64(c.tpsTicks) / elapsed.Seconds()
+		c.tpsStart, c.tpsTicks = now, 0
+	}
+}
+
+// actualTPS returns the simulation ticks taken in the last second.
+func (c *simClock) actualTPS() float64 {
+	return c.tps
+}
+
+func simTickDuration() time.Duration {
+	return time.Second / time.Duration(param.SimRate)
+}
+
+// simTicksMax returns the most ticks taken in a frame.
+func simTicksMax() int {
+	if ticksMax := param.SimRate / simFrameRateMin; ticksMax > 1 {
+		return ticksMax
+	}
+	return 1
+}
+
+func findSimRate(rate int) int {
+	for iRate, simRate := range simRates {
+		if simRate == rate {
+			return iRate
+		}
+	}
+	return findSimRate(param.SimRateDefault)
+}
+
+// applySimRate sets the time step of the simulation. Unknown rates fall back to the default.
+func applySimRate() {
+	display.SimRate = simRates[findSimRate(display.SimRate)]
+	param.SimRate = display.SimRate
+	param.DeltaTime = 1.0 / float64(param.SimRate)
+	clock.reset()
+}
+
+// selectSimRate changes the simulation rate by the offset in the presets and saves it.
+func selectSimRate(offset int) {
+	display.SimRate = simRates[wrapIndex(findSimRate(display.SimRate)+offset, len(simRates))]
+	applySimRate()
+	saveDisplaySettings()
+}
+
+func simRateName() string {
+	return fmt.Sprintf("%d Hz", param.SimRate)
+}
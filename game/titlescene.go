@@ -20,16 +20,20 @@ along with snake-ebiten. If not, see <https://www.gnu.org/licenses/>.
 package game
 
 import (
+	"fmt"
 	"image/color"
 	"math/rand"
+	"strings"
 	"time"
 
 	c "github.com/anilkonac/snake-ebiten/game/core"
+	"github.com/anilkonac/snake-ebiten/game/input"
 	s "github.com/anilkonac/snake-ebiten/game/object/snake"
 	"github.com/anilkonac/snake-ebiten/game/param"
 	"github.com/anilkonac/snake-ebiten/game/shader"
+	"github.com/anilkonac/snake-ebiten/game/skin"
+	"github.com/anilkonac/snake-ebiten/game/sound"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text"
 )
 
@@ -53,21 +57,19 @@ const (
 	titleRectWidth                 = 540
 	titleRectHeight                = 405
 	titleRectRatio                 = 1.0 * titleRectWidth / titleRectHeight
-	titleRectCornerRadiusX         = param.RadiusSnake
-	titleRectCornerRadiusY         = titleRectCornerRadiusX / titleRectRatio
 	titleRectInitialAlpha          = 230 / 255.0
-	titleRectDissapearRate float32 = (80 / 255.0) * param.DeltaTime
+	titleRectDissapearRate float32 = 80 / 255.0 // Alpha decrease per second
 	textTitle                      = "Ssnake"
 	textPressToPlay                = "Press any key to start"
 	textTitleShiftY                = -50
 	textKeyPromptShiftY            = +100
+	textMenuHintShiftY             = 16
 	keyPromptShowTimeSec           = 1.0
 	keyPromptHideTimeSec           = 0.5
 )
 
 var (
-	titleSceneAlive = true
-	colorTitleRect  = &param.ColorSnake2
+	colorTitleRect = &param.ColorSnake2
 
 	snakeColors = [...]*color.RGBA{
 		0: &param.ColorSnake1,
@@ -78,41 +80,48 @@ var (
 )
 
 type titleScene struct {
+	alive             bool
+	menuScene         scene  // Scene to go instead of the game scene
+	mode              modeID // Mode of the game scene
 	titleRectComp     c.TeleCompTriang
 	titleRectAlpha    float32
 	playerSnake       *s.Snake
 	snakes            []s.Snake
-	pressedKeys       []ebiten.Key
 	shaderTitle       *ebiten.Shader
 	titleRectDrawOpts ebiten.DrawTrianglesShaderOptions
+	themeVersion      uint32
 }
 
 func newTitleScene(playerSnake *s.Snake) *titleScene {
+	sound.PlayPlaylist(playlistTitle)
+	s.MouthEnabled = false
+
 	// Create title rect model
 	titleRect := c.RectF32{
-		Pos:       c.Vec32{X: (param.ScreenWidth - titleRectWidth) / 2.0, Y: (param.ScreenHeight - titleRectHeight) / 2.0},
+		Pos:       c.Vec32{X: float32(param.ScreenWidth-titleRectWidth) / 2.0, Y: float32(param.ScreenHeight-titleRectHeight) / 2.0},
 		Size:      c.Vec32{X: titleRectWidth, Y: titleRectHeight},
 		PosInUnit: c.Vec32{X: 0, Y: 0},
 	}
 
 	// Create scene
 	scene := &titleScene{
+		alive:          true,
 		playerSnake:    playerSnake,
 		titleRectAlpha: titleRectInitialAlpha,
 		snakes:         make([]s.Snake, 0, numBotSnakes),
-		pressedKeys:    make([]ebiten.Key, 0, 10),
 		shaderTitle:    shader.New(shader.PathTitle),
 		titleRectDrawOpts: ebiten.DrawTrianglesShaderOptions{
 			Uniforms: map[string]interface{}{
 				"ShowKeyPrompt": float32(0.0),
-				"RadiusTex":     []float32{float32(titleRectCornerRadiusX / titleRectWidth), float32(titleRectCornerRadiusY / titleRectHeight)},
 				"Alpha":         float32(titleRectInitialAlpha),
 			},
 		},
 	}
+	scene.titleRectComp.ScreenSpace = true
 	scene.titleRectComp.SetColor(colorTitleRect)
 	scene.titleRectComp.Update(&titleRect)
 	scene.prepareTitleRects()
+	go scene.keyPromptFlipFlop()
 
 	// Create snakes
 	// -------------
@@ -123,12 +132,14 @@ func newTitleScene(playerSnake *s.Snake) *titleScene {
 		length := dumbSnakeLengthMin + rand.Intn(dumbSnakeLengthDiff)
 		speed := dumbSnakeSpeedMin + rand.Float64()*dumbSnakeSpeedDiff
 		scene.snakes = append(scene.snakes, *s.NewSnakeRandDirLoc(uint16(length), speed, snakeColors[rand.Intn(lenSnakeColors)]))
+		scene.snakes[iSnake].Skin = skin.Random()
 
-		go control(&scene.snakes[iSnake])
+		go scene.control(&scene.snakes[iSnake])
 
 	}
 
-	go control(playerSnake)
+	go scene.control(playerSnake)
+	c.Cam.Reset(playerSnake.UnitHead.HeadCenter)
 
 	return scene
 }
@@ -155,16 +166,27 @@ func (t *titleScene) prepareTitleRects() {
 		(titleRectWidth-boundTextKeyPromptSize.X)/2.0-boundTextKeyPrompt.Min.X,
 		(titleRectHeight-boundTextKeyPromptSize.Y)/2.0-boundTextKeyPrompt.Min.Y+textKeyPromptShiftY, param.ColorBackground)
 
-	// Send images to the shader
+	// Draw controls hint text to both of the images
+	hint := menuHint()
+	boundHint := text.BoundString(fontFaceDebug, hint)
+	for _, img := range [...]*ebiten.Image{titleImage, titleImageKeyPrompt} {
+		text.Draw(img, hint, fontFaceDebug,
+			(titleRectWidth-boundHint.Size().X)/2.0-boundHint.Min.X,
+			titleRectHeight-boundHint.Max.Y-textMenuHintShiftY, param.ColorBackground)
+	}
+
+	// Send images and the corner radius of the theme to the shader
 	t.titleRectDrawOpts.Images[0] = titleImage
 	t.titleRectDrawOpts.Images[1] = titleImageKeyPrompt
-
-	go t.keyPromptFlipFlop()
+	cornerRadiusX := param.CornerRadius
+	cornerRadiusY := cornerRadiusX / titleRectRatio
+	t.titleRectDrawOpts.Uniforms["RadiusTex"] = []float32{cornerRadiusX / titleRectWidth, cornerRadiusY / titleRectHeight}
+	t.themeVersion = param.ThemeVersion
 }
 
 func (t *titleScene) update() bool {
 	// Update bot snakes
-	param.TeleportEnabled = titleSceneAlive
+	param.TeleportEnabled = t.alive
 	for iSnake := 0; iSnake < numBotSnakes; iSnake++ {
 		t.snakes[iSnake].Update(param.MouthAnimStartDistance)
 	}
@@ -172,13 +194,23 @@ func (t *titleScene) update() bool {
 	// Update player snake
 	param.TeleportEnabled = true
 	t.playerSnake.Update(param.MouthAnimStartDistance)
+	c.Cam.Follow(t.playerSnake.UnitHead.HeadCenter)
 
-	if titleSceneAlive {
+	// Redraw the title images with the new theme
+	if t.themeVersion != param.ThemeVersion {
+		t.prepareTitleRects()
+	}
+
+	if t.alive {
 		t.handleKeyPress()
+		if t.menuScene != nil {
+			t.shaderTitle.Dispose()
+			return true
+		}
 
 	} else {
 		// Update transition process to the next scene
-		t.titleRectAlpha -= titleRectDissapearRate
+		t.titleRectAlpha -= titleRectDissapearRate * float32(param.DeltaTime)
 		t.titleRectDrawOpts.Uniforms["Alpha"] = t.titleRectAlpha
 		if t.titleRectAlpha <= 0.0 {
 			t.shaderTitle.Dispose()
@@ -189,11 +221,63 @@ func (t *titleScene) update() bool {
 	return false
 }
 
+// menuScenes are opened by the menu actions on the title screen.
+var menuScenes = [...]struct {
+	action input.Action
+	name   string
+	open   func() scene
+}{
+	{input.ActionOpenControls, "Controls", func() scene { return newControlsScene() }},
+	{input.ActionOpenOptions, "Options", func() scene { return newOptionsScene() }},
+	{input.ActionOpenSkins, "Skins", func() scene { return newSkinsScene() }},
+	{input.ActionOpenBenchmark, "Benchmark", func() scene { return newBenchScene() }},
+	{input.ActionOpenAchievements, "Achievements", func() scene { return newAchievementsScene() }},
+	{input.ActionOpenHighScores, "High scores", func() scene { return newHighScoresScene() }},
+}
+
+// Number of the menu scenes in the first line of the hint
+const menuHintSplit = 4
+
+// menuHint returns the hint of the menu and mode keys with their current bindings.
+func menuHint() string {
+	var hint strings.Builder
+	for iMenu, menu := range menuScenes {
+		if iMenu == menuHintSplit {
+			hint.WriteString("\n")
+		} else if iMenu > 0 {
+			hint.WriteString("   ")
+		}
+		fmt.Fprintf(&hint, "%s: %s", input.KeyLabel(menu.action), menu.name)
+	}
+	hint.WriteString("\n")
+	for iMode := range gameModes {
+		if iMode > 0 {
+			hint.WriteString("   ")
+		}
+		fmt.Fprintf(&hint, "%s: %s", input.KeyLabel(gameModes[iMode].action), gameModes[iMode].name)
+	}
+	return hint.String()
+}
+
 func (t *titleScene) handleKeyPress() {
-	t.pressedKeys = inpututil.AppendPressedKeys(t.pressedKeys[:0])
-	if len(t.pressedKeys) > 0 && titleSceneAlive {
+	for _, menu := range menuScenes {
+		if input.IsActionJustPressed(menu.action) {
+			t.alive = false
+			t.menuScene = menu.open()
+			return
+		}
+	}
+
+	// The mode keys start their modes, any other key starts the classic mode.
+	for iMode := range gameModes {
+		if input.IsActionJustPressed(gameModes[iMode].action) {
+			t.mode = modeID(iMode)
+		}
+	}
+
+	if input.AnyJustPressed() && t.alive {
 		// Start transition process
-		titleSceneAlive = false
+		t.alive = false
 		t.titleRectDrawOpts.Uniforms["ShowKeyPrompt"] = float32(0.0)
 
 		// Increase speeds of snakes other than the player's snake
@@ -204,12 +288,17 @@ func (t *titleScene) handleKeyPress() {
 }
 
 func (t *titleScene) draw(screen *ebiten.Image) {
-	screen.Fill(param.ColorBackground)
+	drawBackground(screen)
+	if worldScrolls() {
+		drawGrid(screen)
+	}
 
-	// Draw bot snakes
+	// Draw bot snakes, they are not copied across the world edges while leaving the scene.
+	param.TeleportEnabled = t.alive
 	for iSnake := 0; iSnake < numBotSnakes; iSnake++ {
 		t.snakes[iSnake].Draw(screen)
 	}
+	param.TeleportEnabled = true
 
 	// Draw player snake
 	t.playerSnake.Draw(screen)
@@ -229,7 +318,7 @@ func (t *titleScene) keyPromptFlipFlop() {
 	showPrompt := true
 
 	halfSecondTicker := time.NewTicker(time.Millisecond * 500)
-	for titleSceneAlive {
+	for t.alive {
 		if showPrompt {
 			t.titleRectDrawOpts.Uniforms["ShowKeyPrompt"] = float32(1.0)
 			for ihalfSecs := 0; ihalfSecs < showTimeHalfSecs; ihalfSecs++ {
@@ -248,12 +337,12 @@ func (t *titleScene) keyPromptFlipFlop() {
 
 // Goroutine
 // control turns given snake at a given time
-func control(snake *s.Snake) {
+func (t *titleScene) control(snake *s.Snake) {
 	const turnTimeMinMs = turnTimeMinSec * 1000
 	const turnTimeDiffMs = turnTimeMaxSec*1000 - turnTimeMinMs
 
 	var dirNew s.DirectionT
-	for titleSceneAlive {
+	for t.alive {
 		// Determine the new direction.
 		dirCurrent := snake.LastDirection()
 
@@ -11,6 +11,7 @@ require (
 	github.com/ebitengine/purego v0.0.0-20220829192423-0acfce66fb4a // indirect
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20220806181222-55e207c401ad // indirect
 	github.com/hajimehoshi/file2byteslice v1.0.0 // indirect
+	github.com/hajimehoshi/go-mp3 v0.3.3 // indirect
 	github.com/hajimehoshi/oto/v2 v2.3.0 // indirect
 	github.com/jezek/xgb v1.0.1 // indirect
 	github.com/jfreymuth/oggvorbis v1.0.4 // indirect
@@ -6,8 +6,7 @@ import (
 )
 
 func main() {
-	ebiten.SetWindowSize(g.ScreenSize())
-	ebiten.SetWindowTitle("Ssnake")
+	g.InitWindow()
 	// ebiten.SetFPSMode(ebiten.FPSModeVsyncOffMaximum)
 	ebiten.RunGame(g.NewGame())
 }